@@ -11,7 +11,10 @@
 
 package marionette
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+)
 
 const (
 	// mouse buttons
@@ -32,6 +35,22 @@ const (
 	NoneType    ActionType = "none"
 	KeyType     ActionType = "key"
 	PointerType ActionType = "pointer"
+	WheelType   ActionType = "wheel"
+)
+
+const (
+	// pointer types of pointer input source
+	PointerMouse = "mouse"
+	PointerPen   = "pen"
+	PointerTouch = "touch"
+)
+
+const (
+	// ids of default input sources used by ActionChain shortcuts
+	DefaultNoneSource  = "none"
+	DefaultKeySource   = "keyboard"
+	DefaultMouseSource = "mouse"
+	DefaultWheelSource = "wheel"
 )
 
 // ActionSubType denotes possible actions
@@ -45,26 +64,40 @@ const (
 	PointerDownAction ActionSubType = "pointerDown"
 	PointerUpAction   ActionSubType = "pointerUp"
 	PointerMoveAction ActionSubType = "pointerMove"
+	ScrollAction      ActionSubType = "scroll"
 	// unsupported, leave here for future usage
 	PointerCancelAction ActionSubType = "pointerCancel"
 )
 
-var validSubType = map[ActionSubType]ActionType{
-	PauseAction:         NoneType,
-	KeyDownAction:       KeyType,
-	KeyUpAction:         KeyType,
-	PointerDownAction:   PointerType,
-	PointerUpAction:     PointerType,
-	PointerMoveAction:   PointerType,
-	PointerCancelAction: PointerType,
+// ActionSequence represents actions of an input source
+//
+// Each action occupies a tick. Actions of different sources in same tick are
+// dispatched together.
+type ActionSequence struct {
+	ID          string
+	Type        ActionType
+	PointerType string // only for PointerType, PointerMouse if empty
+	Actions     []*ActionItem
 }
 
-// ActionSequence represents an ui action
-type ActionSequence struct {
-	ID          string        `json:"id"`
-	Type        ActionType    `json:"type"`
-	PointerType string        `json:"pointerType,omitempty"` // currently only "mouse" is supported
-	Actions     []*ActionItem `json:"actions"`
+func (s *ActionSequence) MarshalJSON() (ret []byte, err error) {
+	data := map[string]interface{}{
+		"id":      s.ID,
+		"type":    s.Type,
+		"actions": s.Actions,
+	}
+	if s.Actions == nil {
+		data["actions"] = []*ActionItem{}
+	}
+	if s.Type == PointerType {
+		typ := s.PointerType
+		if typ == "" {
+			typ = PointerMouse
+		}
+		data["parameters"] = map[string]string{"pointerType": typ}
+	}
+
+	return json.Marshal(data)
 }
 
 // ActionItem represents an input event
@@ -76,6 +109,64 @@ type ActionItem struct {
 	Origin   string        `json:"origin,omitempty"` // can be "viewport" or "pointer"
 	X        int           `json:"x,omitempty"`
 	Y        int           `json:"y,omitempty"`
+	DeltaX   int           `json:"deltaX,omitempty"`
+	DeltaY   int           `json:"deltaY,omitempty"`
+	// if set, X and Y are relative to center of the element, and Origin is ignored
+	OriginElement *WebElement `json:"-"`
+	// extra properties of pen and touch pointers
+	Properties *PointerProperties `json:"-"`
+}
+
+// PointerProperties represents extra properties of pointer events
+//
+// Zero-valued fields are omitted, so browser defaults are used.
+//
+// See https://w3c.github.io/pointerevents/#pointerevent-interface
+type PointerProperties struct {
+	// size of contact geometry in CSS pixels
+	Width  float64
+	Height float64
+	// normalized pressure, 0 to 1
+	Pressure           float64
+	TangentialPressure float64
+	// tilt in degrees, -90 to 90
+	TiltX int
+	TiltY int
+	// rotation in degrees, 0 to 359
+	Twist int
+	// angles in radians
+	AltitudeAngle float64
+	AzimuthAngle  float64
+}
+
+func (p *PointerProperties) fill(data map[string]interface{}) {
+	if p == nil {
+		return
+	}
+	set := func(key string, v float64) {
+		if v != 0 {
+			data[key] = v
+		}
+	}
+	set("width", p.Width)
+	set("height", p.Height)
+	set("pressure", p.Pressure)
+	set("tangentialPressure", p.TangentialPressure)
+	set("tiltX", float64(p.TiltX))
+	set("tiltY", float64(p.TiltY))
+	set("twist", float64(p.Twist))
+	set("altitudeAngle", p.AltitudeAngle)
+	set("azimuthAngle", p.AzimuthAngle)
+}
+
+func (i ActionItem) origin() (ret interface{}) {
+	if i.OriginElement != nil {
+		return i.OriginElement
+	}
+	if i.Origin == "" {
+		return "viewport"
+	}
+	return i.Origin
 }
 
 func (i ActionItem) MarshalJSON() (ret []byte, err error) {
@@ -86,10 +177,19 @@ func (i ActionItem) MarshalJSON() (ret []byte, err error) {
 	switch i.Type {
 	case PointerUpAction, PointerDownAction:
 		data["button"] = i.Button
+		i.Properties.fill(data)
 	case PointerMoveAction:
 		data["x"] = int(i.X)
 		data["y"] = int(i.Y)
-		data["origin"] = i.Origin
+		data["origin"] = i.origin()
+		data["duration"] = i.Duration
+		i.Properties.fill(data)
+	case ScrollAction:
+		data["x"] = i.X
+		data["y"] = i.Y
+		data["deltaX"] = i.DeltaX
+		data["deltaY"] = i.DeltaY
+		data["origin"] = i.origin()
 		data["duration"] = i.Duration
 	case KeyUpAction, KeyDownAction:
 		data["value"] = i.Value
@@ -101,80 +201,168 @@ func (i ActionItem) MarshalJSON() (ret []byte, err error) {
 }
 
 // ActionChain helps you to build list of ui actions
-type ActionChain []*ActionSequence
+//
+// Actions are added to named input sources, and every action takes a new tick
+// unless grouped by Together. Other sources are padded with pauses
+// automatically, so actions are dispatched in the order you add them:
+//
+//	var chain marionette.ActionChain
+//	kbd := chain.Keyboard("kbd")
+//	mouse := chain.Pointer("mouse", marionette.PointerMouse)
+//	kbd.Down(marionette.KeyControl)    // tick 0
+//	mouse.MoveTo(10, 10, 0)            // tick 1
+//	mouse.Click(marionette.MouseLeft)  // tick 2, 3
+//	kbd.Up(marionette.KeyControl)      // tick 4
+//
+// Methods of ActionChain like KeyDown or MouseClick are shortcuts using default
+// sources (DefaultKeySource and DefaultMouseSource).
+//
+// Errors like reusing an id with another type of input source (creating a touch
+// pointer with DefaultMouseSource and calling MouseClick) are recorded in the
+// chain and reported by Err. Actions causing the error are discarded.
+type ActionChain struct {
+	// input sources in creation order
+	Sequences []*ActionSequence
+
+	inGroup    bool
+	groupStart int
+	err        error
+}
 
-func (b *ActionChain) prepare(sub ActionSubType) {
-	typ := validSubType[sub]
-	l := len(*b)
-	if l > 0 && (*b)[l-1].Type == typ {
-		return
+func (b ActionChain) MarshalJSON() (ret []byte, err error) {
+	if b.Sequences == nil {
+		return []byte("[]"), nil
 	}
+	return json.Marshal(b.Sequences)
+}
 
-	seq := &ActionSequence{
-		ID:      string(typ),
-		Type:    typ,
-		Actions: []*ActionItem{},
+// Ticks returns number of ticks in the chain
+func (b *ActionChain) Ticks() (ret int) {
+	for _, s := range b.Sequences {
+		if l := len(s.Actions); l > ret {
+			ret = l
+		}
 	}
-	if typ == PointerType {
-		seq.PointerType = "mouse"
+	return
+}
+
+// Source retrieves input source by id, or nil if not found
+func (b *ActionChain) Source(id string) (ret *ActionSequence) {
+	for _, s := range b.Sequences {
+		if s.ID == id {
+			return s
+		}
 	}
+	return
+}
 
-	*b = append(*b, seq)
+// Err returns first error occurred when building the chain
+func (b *ActionChain) Err() (err error) {
+	return b.err
 }
 
-func (b *ActionChain) addAction(act *ActionItem) (z *ActionChain) {
-	cur := (*b)[len(*b)-1]
-	cur.Actions = append(cur.Actions, act)
-	return b
+// fail records err if no error is recorded yet
+func (b *ActionChain) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
 }
 
-// Wait creates a no-op action
-func (b *ActionChain) Wait(ms int) (z *ActionChain) {
-	b.prepare(PauseAction)
-	act := &ActionItem{
-		Type: PauseAction,
+func (b *ActionChain) source(id string, typ ActionType, pointerType string) (ret *ActionSequence) {
+	ret = &ActionSequence{
+		ID:          id,
+		Type:        typ,
+		PointerType: pointerType,
 	}
-	if ms > 0 {
-		act.Duration = ms
+	if s := b.Source(id); s != nil {
+		if s.Type == typ && (typ != PointerType || s.PointerType == pointerType) {
+			return s
+		}
+		// detached from the chain, so actions are discarded
+		b.fail(errors.New("marionette: input source " + id + " is already used as another type"))
+		return
 	}
 
-	return b.addAction(act)
+	b.Sequences = append(b.Sequences, ret)
+	return
 }
 
-// MouseDown creates a PointerDown action
-func (b *ActionChain) MouseDown(btn int) (z *ActionChain) {
-	// invalid button, ignore
-	if btn <= beginOfValidMouseBtn || btn >= endOfValidMouseBtn {
+// add appends act to src at proper tick, padding with pauses if needed
+func (b *ActionChain) add(src *ActionSequence, act *ActionItem) {
+	tick := b.Ticks()
+	if b.inGroup {
+		tick = b.groupStart
+	}
+	for len(src.Actions) < tick {
+		src.Actions = append(src.Actions, &ActionItem{Type: PauseAction})
+	}
+	src.Actions = append(src.Actions, act)
+}
+
+// Together runs f and puts actions added in it into the same tick
+//
+// If an input source gets more than one action in f, latter actions take next
+// ticks. Nested calls are merged into outermost one.
+//
+//	// press ctrl and mouse button at once
+//	chain.Together(func() {
+//	    kbd.Down(marionette.KeyControl)
+//	    mouse.Down(marionette.MouseLeft)
+//	})
+func (b *ActionChain) Together(f func()) (z *ActionChain) {
+	if b.inGroup {
+		f()
 		return b
 	}
 
-	typ := PointerDownAction
-	b.prepare(typ)
+	b.inGroup = true
+	b.groupStart = b.Ticks()
+	defer func() { b.inGroup = false }()
+	f()
+	return b
+}
 
-	act := &ActionItem{
-		Type:   typ,
-		Button: btn,
-	}
+// None retrieves (or creates) a "none" input source, which can only pause
+func (b *ActionChain) None(id string) (ret *NoneSource) {
+	return &NoneSource{chain: b, seq: b.source(id, NoneType, "")}
+}
 
-	return b.addAction(act)
+// Keyboard retrieves (or creates) a key input source
+func (b *ActionChain) Keyboard(id string) (ret *KeySource) {
+	return &KeySource{chain: b, seq: b.source(id, KeyType, "")}
 }
 
-// MouseUp creates a PointerUp action
-func (b *ActionChain) MouseUp(btn int) (z *ActionChain) {
-	// invalid button, ignore
-	if btn <= beginOfValidMouseBtn || btn >= endOfValidMouseBtn {
-		return b
+// Pointer retrieves (or creates) a pointer input source
+//
+// pointerType can be PointerMouse, PointerPen or PointerTouch.
+func (b *ActionChain) Pointer(id, pointerType string) (ret *PointerSource) {
+	if pointerType == "" {
+		pointerType = PointerMouse
 	}
+	return &PointerSource{chain: b, seq: b.source(id, PointerType, pointerType)}
+}
 
-	typ := PointerUpAction
-	b.prepare(typ)
+// Wheel retrieves (or creates) a wheel input source
+func (b *ActionChain) Wheel(id string) (ret *WheelSource) {
+	return &WheelSource{chain: b, seq: b.source(id, WheelType, "")}
+}
 
-	act := &ActionItem{
-		Type:   typ,
-		Button: btn,
-	}
+// Wait creates a no-op action in default "none" source
+func (b *ActionChain) Wait(ms int) (z *ActionChain) {
+	b.None(DefaultNoneSource).Pause(ms)
+	return b
+}
 
-	return b.addAction(act)
+// MouseDown creates a PointerDown action in default mouse source
+func (b *ActionChain) MouseDown(btn int) (z *ActionChain) {
+	b.Pointer(DefaultMouseSource, PointerMouse).Down(btn)
+	return b
+}
+
+// MouseUp creates a PointerUp action in default mouse source
+func (b *ActionChain) MouseUp(btn int) (z *ActionChain) {
+	b.Pointer(DefaultMouseSource, PointerMouse).Up(btn)
+	return b
 }
 
 // MouseClick is identical to b.MouseDown(btn).MouseUp(btn)
@@ -184,67 +372,228 @@ func (b *ActionChain) MouseClick(btn int) (z *ActionChain) {
 
 // MouseMoveTo creates a PointerMove action relative to vieport origin
 func (b *ActionChain) MouseMoveTo(x, y, duration int) (z *ActionChain) {
-	if duration < 0 {
-		duration = 0
+	b.Pointer(DefaultMouseSource, PointerMouse).MoveTo(x, y, duration)
+	return b
+}
+
+// MouseMoveToElement creates a PointerMove action relative to center of el
+func (b *ActionChain) MouseMoveToElement(el *WebElement, x, y, duration int) (z *ActionChain) {
+	b.Pointer(DefaultMouseSource, PointerMouse).MoveToElement(el, x, y, duration)
+	return b
+}
+
+// MouseMoveFor creates a PointerMove action relative to current mouse position
+func (b *ActionChain) MouseMoveFor(x, y, duration int) (z *ActionChain) {
+	b.Pointer(DefaultMouseSource, PointerMouse).MoveBy(x, y, duration)
+	return b
+}
+
+// ScrollBy creates a Scroll action at (x, y) of viewport in default wheel source
+func (b *ActionChain) ScrollBy(x, y, deltaX, deltaY, duration int) (z *ActionChain) {
+	b.Wheel(DefaultWheelSource).ScrollBy(x, y, deltaX, deltaY, duration)
+	return b
+}
+
+// ScrollElement creates a Scroll action at center of el in default wheel source
+func (b *ActionChain) ScrollElement(el *WebElement, deltaX, deltaY, duration int) (z *ActionChain) {
+	b.Wheel(DefaultWheelSource).ScrollElement(el, deltaX, deltaY, duration)
+	return b
+}
+
+// KeyDown creates a KeyDown action in default key source
+func (b *ActionChain) KeyDown(key string) (z *ActionChain) {
+	b.Keyboard(DefaultKeySource).Down(key)
+	return b
+}
+
+// KeyUp creates a KeyUp action in default key source
+func (b *ActionChain) KeyUp(key string) (z *ActionChain) {
+	b.Keyboard(DefaultKeySource).Up(key)
+	return b
+}
+
+// KeyPress is identical to b.KeyDown(key).KeyUp(key)
+func (b *ActionChain) KeyPress(key string) (z *ActionChain) {
+	return b.KeyDown(key).KeyUp(key)
+}
+
+// NoneSource adds actions to a "none" input source
+type NoneSource struct {
+	chain *ActionChain
+	seq   *ActionSequence
+}
+
+// Pause creates a no-op action lasts for ms milliseconds
+func (s *NoneSource) Pause(ms int) (z *NoneSource) {
+	s.chain.add(s.seq, pauseItem(ms))
+	return s
+}
+
+func pauseItem(ms int) (ret *ActionItem) {
+	ret = &ActionItem{Type: PauseAction}
+	if ms > 0 {
+		ret.Duration = ms
 	}
+	return
+}
 
-	typ := PointerMoveAction
-	b.prepare(typ)
+// KeySource adds actions to a key input source
+type KeySource struct {
+	chain *ActionChain
+	seq   *ActionSequence
+}
+
+// Pause creates a no-op action lasts for ms milliseconds
+func (s *KeySource) Pause(ms int) (z *KeySource) {
+	s.chain.add(s.seq, pauseItem(ms))
+	return s
+}
+
+// Down creates a KeyDown action
+func (s *KeySource) Down(key string) (z *KeySource) {
+	s.chain.add(s.seq, &ActionItem{Type: KeyDownAction, Value: key})
+	return s
+}
 
-	act := &ActionItem{
-		Type:     typ,
-		X:        x,
-		Y:        y,
-		Duration: duration,
-		Origin:   "viewport",
+// Up creates a KeyUp action
+func (s *KeySource) Up(key string) (z *KeySource) {
+	s.chain.add(s.seq, &ActionItem{Type: KeyUpAction, Value: key})
+	return s
+}
+
+// Press is identical to s.Down(key).Up(key)
+func (s *KeySource) Press(key string) (z *KeySource) {
+	return s.Down(key).Up(key)
+}
+
+// PointerSource adds actions to a pointer input source
+type PointerSource struct {
+	chain *ActionChain
+	seq   *ActionSequence
+	props *PointerProperties
+}
+
+// With returns a copy of s, which attaches p to following down/up/move actions
+//
+//	pen := chain.Pointer("pen", marionette.PointerPen)
+//	pen.MoveTo(10, 10, 0)
+//	pen.With(marionette.PointerProperties{Pressure: 0.8, TiltX: 30}).
+//	    Down(marionette.MouseLeft).
+//	    MoveBy(50, 0, 200).
+//	    Up(marionette.MouseLeft)
+func (s *PointerSource) With(p PointerProperties) (ret *PointerSource) {
+	return &PointerSource{chain: s.chain, seq: s.seq, props: &p}
+}
+
+// Pause creates a no-op action lasts for ms milliseconds
+func (s *PointerSource) Pause(ms int) (z *PointerSource) {
+	s.chain.add(s.seq, pauseItem(ms))
+	return s
+}
+
+// Down creates a PointerDown action, invalid button is ignored
+func (s *PointerSource) Down(btn int) (z *PointerSource) {
+	if btn <= beginOfValidMouseBtn || btn >= endOfValidMouseBtn {
+		return s
 	}
-	return b.addAction(act)
+	s.chain.add(s.seq, &ActionItem{Type: PointerDownAction, Button: btn, Properties: s.props})
+	return s
 }
 
-// MouseMoveFor creates a PointerMove action relative to current mouse position
-func (b *ActionChain) MouseMoveFor(x, y, duration int) (z *ActionChain) {
+// Up creates a PointerUp action, invalid button is ignored
+func (s *PointerSource) Up(btn int) (z *PointerSource) {
+	if btn <= beginOfValidMouseBtn || btn >= endOfValidMouseBtn {
+		return s
+	}
+	s.chain.add(s.seq, &ActionItem{Type: PointerUpAction, Button: btn, Properties: s.props})
+	return s
+}
+
+// Click is identical to s.Down(btn).Up(btn)
+func (s *PointerSource) Click(btn int) (z *PointerSource) {
+	return s.Down(btn).Up(btn)
+}
+
+func (s *PointerSource) move(x, y, duration int, origin string) (z *PointerSource) {
 	if duration < 0 {
 		duration = 0
 	}
+	s.chain.add(s.seq, &ActionItem{
+		Type:       PointerMoveAction,
+		X:          x,
+		Y:          y,
+		Duration:   duration,
+		Origin:     origin,
+		Properties: s.props,
+	})
+	return s
+}
 
-	typ := PointerMoveAction
-	b.prepare(typ)
+// MoveTo creates a PointerMove action relative to viewport origin
+func (s *PointerSource) MoveTo(x, y, duration int) (z *PointerSource) {
+	return s.move(x, y, duration, "viewport")
+}
 
-	act := &ActionItem{
-		Type:     typ,
-		X:        x,
-		Y:        y,
-		Duration: duration,
-		Origin:   "pointer",
-	}
-	return b.addAction(act)
+// MoveBy creates a PointerMove action relative to current pointer position
+func (s *PointerSource) MoveBy(x, y, duration int) (z *PointerSource) {
+	return s.move(x, y, duration, "pointer")
 }
 
-// KeyDown creates a KeyDown action
-func (b *ActionChain) KeyDown(key string) (z *ActionChain) {
-	typ := KeyDownAction
-	b.prepare(typ)
-	act := &ActionItem{
-		Type:  typ,
-		Value: key,
+// MoveToElement creates a PointerMove action relative to center of el
+func (s *PointerSource) MoveToElement(el *WebElement, x, y, duration int) (z *PointerSource) {
+	if duration < 0 {
+		duration = 0
 	}
+	s.chain.add(s.seq, &ActionItem{
+		Type:          PointerMoveAction,
+		X:             x,
+		Y:             y,
+		Duration:      duration,
+		OriginElement: el,
+		Properties:    s.props,
+	})
+	return s
+}
 
-	return b.addAction(act)
+// WheelSource adds actions to a wheel input source
+type WheelSource struct {
+	chain *ActionChain
+	seq   *ActionSequence
 }
 
-// KeyUp creates a KeyUp action
-func (b *ActionChain) KeyUp(key string) (z *ActionChain) {
-	typ := KeyUpAction
-	b.prepare(typ)
-	act := &ActionItem{
-		Type:  typ,
-		Value: key,
+// Pause creates a no-op action lasts for ms milliseconds
+func (s *WheelSource) Pause(ms int) (z *WheelSource) {
+	s.chain.add(s.seq, pauseItem(ms))
+	return s
+}
+
+func (s *WheelSource) scroll(el *WebElement, x, y, deltaX, deltaY, duration int) (z *WheelSource) {
+	if duration < 0 {
+		duration = 0
 	}
+	s.chain.add(s.seq, &ActionItem{
+		Type:          ScrollAction,
+		X:             x,
+		Y:             y,
+		DeltaX:        deltaX,
+		DeltaY:        deltaY,
+		Duration:      duration,
+		OriginElement: el,
+	})
+	return s
+}
 
-	return b.addAction(act)
+// ScrollBy creates a Scroll action at (x, y) relative to viewport
+func (s *WheelSource) ScrollBy(x, y, deltaX, deltaY, duration int) (z *WheelSource) {
+	return s.scroll(nil, x, y, deltaX, deltaY, duration)
 }
 
-// KeyPress is identical to b.KeyDown(key).KeyUp(key)
-func (b *ActionChain) KeyPress(key string) (z *ActionChain) {
-	return b.KeyDown(key).KeyUp(key)
+// ScrollElement creates a Scroll action at center of el
+func (s *WheelSource) ScrollElement(el *WebElement, deltaX, deltaY, duration int) (z *WheelSource) {
+	return s.scroll(el, 0, 0, deltaX, deltaY, duration)
+}
+
+// ScrollElementAt creates a Scroll action at (x, y) relative to center of el
+func (s *WheelSource) ScrollElementAt(el *WebElement, x, y, deltaX, deltaY, duration int) (z *WheelSource) {
+	return s.scroll(el, x, y, deltaX, deltaY, duration)
 }
@@ -0,0 +1,129 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func testActionJSON(t *testing.T, chain ActionChain, expected string) {
+	data, err := json.Marshal(chain)
+	if err != nil {
+		t.Fatalf("cannot marshal actions: %s", err)
+	}
+	if str := string(data); str != expected {
+		t.Fatalf("unexpected result:\n%s\nexpected:\n%s", str, expected)
+	}
+}
+
+const (
+	jsonPause = `{"duration":0,"type":"pause"}`
+	jsonKey   = `{"type":"keyDown","value":"` + KeyControl + `"}`
+	jsonKeyUp = `{"type":"keyUp","value":"` + KeyControl + `"}`
+	jsonDown  = `{"button":0,"type":"pointerDown"}`
+	jsonUp    = `{"button":0,"type":"pointerUp"}`
+	jsonMove  = `{"duration":0,"origin":"viewport","type":"pointerMove","x":10,"y":20}`
+)
+
+func TestActionChainEmpty(t *testing.T) {
+	testActionJSON(t, ActionChain{}, `[]`)
+}
+
+func TestActionChainShortcuts(t *testing.T) {
+	var chain ActionChain
+	chain.KeyDown(KeyControl).
+		MouseMoveTo(10, 20, 0).
+		MouseClick(MouseLeft).
+		KeyUp(KeyControl)
+
+	// one sequence per source, aligned by pauses
+	testActionJSON(t, chain, `[`+
+		`{"actions":[`+jsonKey+`,`+jsonPause+`,`+jsonPause+`,`+jsonPause+`,`+jsonKeyUp+`],"id":"keyboard","type":"key"},`+
+		`{"actions":[`+jsonPause+`,`+jsonMove+`,`+jsonDown+`,`+jsonUp+`],"id":"mouse","parameters":{"pointerType":"mouse"},"type":"pointer"}`+
+		`]`)
+}
+
+func TestActionChainTogether(t *testing.T) {
+	var chain ActionChain
+	kbd := chain.Keyboard("kbd")
+	mouse := chain.Pointer("m", PointerMouse)
+
+	mouse.MoveTo(10, 20, 0)
+	chain.Together(func() {
+		kbd.Down(KeyControl)
+		mouse.Down(MouseLeft).Up(MouseLeft)
+	})
+	kbd.Up(KeyControl)
+
+	testActionJSON(t, chain, `[`+
+		`{"actions":[`+jsonPause+`,`+jsonKey+`,`+jsonPause+`,`+jsonKeyUp+`],"id":"kbd","type":"key"},`+
+		`{"actions":[`+jsonMove+`,`+jsonDown+`,`+jsonUp+`],"id":"m","parameters":{"pointerType":"mouse"},"type":"pointer"}`+
+		`]`)
+	if n := chain.Ticks(); n != 4 {
+		t.Fatalf("expected 4 ticks, got %d", n)
+	}
+}
+
+func TestActionChainSources(t *testing.T) {
+	var chain ActionChain
+	chain.Pointer("pen", PointerPen).Pause(5)
+	chain.None("idle").Pause(10)
+	chain.Wait(0)
+
+	testActionJSON(t, chain, `[`+
+		`{"actions":[{"duration":5,"type":"pause"}],"id":"pen","parameters":{"pointerType":"pen"},"type":"pointer"},`+
+		`{"actions":[`+jsonPause+`,{"duration":10,"type":"pause"}],"id":"idle","type":"none"},`+
+		`{"actions":[`+jsonPause+`,`+jsonPause+`,`+jsonPause+`],"id":"none","type":"none"}`+
+		`]`)
+
+	// same source is reused
+	if chain.Pointer("pen", PointerPen); len(chain.Sequences) != 3 {
+		t.Fatalf("unexpected sources: %d", len(chain.Sequences))
+	}
+	if chain.Err() != nil {
+		t.Fatalf("unexpected error: %s", chain.Err())
+	}
+
+	// reusing id with another type
+	chain.Keyboard("pen").Down("a")
+	if chain.Err() == nil {
+		t.Fatal("expected error when reusing id with another type")
+	}
+	if l := len(chain.Source("pen").Actions); l != 1 || len(chain.Sequences) != 3 {
+		t.Fatalf("actions of invalid source are not discarded: %d", l)
+	}
+
+	var mouse ActionChain
+	mouse.Pointer(DefaultMouseSource, PointerTouch)
+	mouse.MouseClick(MouseLeft)
+	if mouse.Err() == nil {
+		t.Fatal("expected error when reusing default mouse with touch")
+	}
+}
+
+func TestActionChainElementOrigin(t *testing.T) {
+	el := &WebElement{Type: ElementType, UUID: "uuid"}
+	var chain ActionChain
+	chain.MouseMoveToElement(el, 1, -1, 0).
+		ScrollElement(el, 0, 100, 50).
+		ScrollBy(10, 20, 5, 0, 0)
+
+	origin := `{"` + ElementType + `":"uuid"}`
+	testActionJSON(t, chain, `[`+
+		`{"actions":[{"duration":0,"origin":`+origin+`,"type":"pointerMove","x":1,"y":-1}],"id":"mouse","parameters":{"pointerType":"mouse"},"type":"pointer"},`+
+		`{"actions":[`+jsonPause+`,`+
+		`{"deltaX":0,"deltaY":100,"duration":50,"origin":`+origin+`,"type":"scroll","x":0,"y":0},`+
+		`{"deltaX":5,"deltaY":0,"duration":0,"origin":"viewport","type":"scroll","x":10,"y":20}`+
+		`],"id":"wheel","type":"wheel"}`+
+		`]`)
+}
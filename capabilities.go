@@ -0,0 +1,304 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	// possible unhandledPromptBehavior
+	PromptDismiss          = "dismiss"
+	PromptAccept           = "accept"
+	PromptDismissAndNotify = "dismiss and notify"
+	PromptAcceptAndNotify  = "accept and notify"
+	PromptIgnore           = "ignore"
+)
+
+const (
+	// possible pageLoadStrategy
+	PageLoadNone   = "none"
+	PageLoadEager  = "eager"
+	PageLoadNormal = "normal"
+)
+
+// FirefoxLog represents log options in moz:firefoxOptions
+type FirefoxLog struct {
+	// can be trace, debug, config, info, warn, error or fatal
+	Level string `json:"level"`
+}
+
+// FirefoxOptions represents "moz:firefoxOptions" capability
+//
+// See https://firefox-source-docs.mozilla.org/testing/geckodriver/Capabilities.html
+type FirefoxOptions struct {
+	Binary  string                 `json:"binary,omitempty"`
+	Args    []string               `json:"args,omitempty"`
+	Profile string                 `json:"profile,omitempty"`
+	Log     *FirefoxLog            `json:"log,omitempty"`
+	Prefs   map[string]interface{} `json:"prefs,omitempty"`
+	Env     map[string]string      `json:"env,omitempty"`
+}
+
+var validLogLevels = map[string]bool{
+	"trace": true, "debug": true, "config": true, "info": true,
+	"warn": true, "error": true, "fatal": true,
+}
+
+// Validate checks if options are valid
+func (o *FirefoxOptions) Validate() (err error) {
+	if o.Log != nil && !validLogLevels[o.Log.Level] {
+		return &ErrCapability{
+			Key:    "moz:firefoxOptions",
+			Reason: "unknown log level " + o.Log.Level,
+		}
+	}
+
+	for k, v := range o.Prefs {
+		switch v.(type) {
+		case string, bool, int, int64, float64:
+		default:
+			return &ErrCapability{
+				Key:    "moz:firefoxOptions",
+				Reason: "unsupported type of pref " + k,
+			}
+		}
+	}
+
+	return
+}
+
+// keys of Capabilities fields, computed from struct tags
+var capKeys = func() (ret map[string]bool) {
+	ret = map[string]bool{}
+	t := reflect.TypeOf(Capabilities{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			ret[name] = true
+		}
+	}
+	return
+}()
+
+// capabilities without custom json methods
+type capAlias Capabilities
+
+func (c *Capabilities) MarshalJSON() (data []byte, err error) {
+	buf, err := json.Marshal((*capAlias)(c))
+	if err != nil {
+		return
+	}
+
+	m := map[string]interface{}{}
+	if err = json.Unmarshal(buf, &m); err != nil {
+		return
+	}
+	for k, v := range c.Extra {
+		if !capKeys[k] {
+			m[k] = v
+		}
+	}
+	if c.EnableDebugger {
+		m["moz:debuggerAddress"] = true
+	}
+
+	return json.Marshal(m)
+}
+
+func (c *Capabilities) UnmarshalJSON(data []byte) (err error) {
+	m := map[string]json.RawMessage{}
+	if err = json.Unmarshal(data, &m); err != nil {
+		return
+	}
+
+	// moz:debuggerAddress is a bool in request and a string in response
+	if v, ok := m["moz:debuggerAddress"]; ok && string(v) == "true" {
+		delete(m, "moz:debuggerAddress")
+		c.EnableDebugger = true
+		if data, err = json.Marshal(m); err != nil {
+			return
+		}
+	}
+
+	if err = json.Unmarshal(data, (*capAlias)(c)); err != nil {
+		return
+	}
+
+	c.Extra = nil
+	for k, v := range m {
+		if capKeys[k] {
+			continue
+		}
+		var x interface{}
+		if err = json.Unmarshal(v, &x); err != nil {
+			return
+		}
+		if c.Extra == nil {
+			c.Extra = map[string]interface{}{}
+		}
+		c.Extra[k] = x
+	}
+
+	return
+}
+
+// Validate checks if capabilities are valid for requesting new session
+func (c *Capabilities) Validate() (err error) {
+	switch c.PageLoadStrategy {
+	case "", PageLoadNone, PageLoadEager, PageLoadNormal:
+	default:
+		return &ErrCapability{
+			Key:    "pageLoadStrategy",
+			Reason: "unknown strategy " + c.PageLoadStrategy,
+		}
+	}
+
+	switch c.UnhandledPromptBehavior {
+	case "", PromptDismiss, PromptAccept, PromptDismissAndNotify,
+		PromptAcceptAndNotify, PromptIgnore:
+	default:
+		return &ErrCapability{
+			Key:    "unhandledPromptBehavior",
+			Reason: "unknown behavior " + c.UnhandledPromptBehavior,
+		}
+	}
+
+	if t := c.Timeouts; t != nil && (t.Implicit < 0 || t.PageLoad < 0 || t.Script < 0) {
+		return &ErrCapability{
+			Key:    "timeouts",
+			Reason: "negative timeout",
+		}
+	}
+
+	if p := c.Proxy; p != nil {
+		switch p.Type {
+		case "pac", "direct", "autodetect", "system", "manual":
+		default:
+			return &ErrCapability{
+				Key:    "proxy",
+				Reason: "unknown proxy type " + p.Type,
+			}
+		}
+	}
+
+	if c.FirefoxOptions != nil {
+		if err = c.FirefoxOptions.Validate(); err != nil {
+			return
+		}
+	}
+
+	for k := range c.Extra {
+		if !strings.Contains(k, ":") {
+			return &ErrCapability{
+				Key:    k,
+				Reason: "unknown capability must be vendor prefixed",
+			}
+		}
+	}
+
+	return
+}
+
+// CapabilitiesRequest represents capabilities used to create new session
+//
+// See https://w3c.github.io/webdriver/#processing-capabilities
+type CapabilitiesRequest struct {
+	AlwaysMatch *Capabilities   `json:"alwaysMatch,omitempty"`
+	FirstMatch  []*Capabilities `json:"firstMatch,omitempty"`
+}
+
+// Validate checks every capabilities in the request
+//
+// It also ensures no key presents in both AlwaysMatch and any of FirstMatch.
+func (r *CapabilitiesRequest) Validate() (err error) {
+	always := map[string]interface{}{}
+	if r.AlwaysMatch != nil {
+		if err = r.AlwaysMatch.Validate(); err != nil {
+			return
+		}
+		if always, err = capToMap(r.AlwaysMatch); err != nil {
+			return
+		}
+	}
+
+	for _, c := range r.FirstMatch {
+		if err = c.Validate(); err != nil {
+			return
+		}
+
+		var m map[string]interface{}
+		if m, err = capToMap(c); err != nil {
+			return
+		}
+		for k := range m {
+			if _, ok := always[k]; ok {
+				return &ErrCapability{
+					Key:    k,
+					Reason: "presents in both alwaysMatch and firstMatch",
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// Merge merges AlwaysMatch with each FirstMatch, in order
+//
+// It always returns at least one Capabilities.
+func (r *CapabilitiesRequest) Merge() (ret []*Capabilities, err error) {
+	always := map[string]interface{}{}
+	if r.AlwaysMatch != nil {
+		if always, err = capToMap(r.AlwaysMatch); err != nil {
+			return
+		}
+	}
+
+	first := r.FirstMatch
+	if len(first) == 0 {
+		first = []*Capabilities{{}}
+	}
+
+	ret = make([]*Capabilities, 0, len(first))
+	for _, c := range first {
+		var m map[string]interface{}
+		if m, err = capToMap(c); err != nil {
+			return
+		}
+		for k, v := range always {
+			m[k] = v
+		}
+
+		buf, _ := json.Marshal(m)
+		var merged Capabilities
+		if err = json.Unmarshal(buf, &merged); err != nil {
+			return
+		}
+		ret = append(ret, &merged)
+	}
+
+	return
+}
+
+func capToMap(c *Capabilities) (ret map[string]interface{}, err error) {
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(buf, &ret)
+	return
+}
@@ -0,0 +1,142 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCapabilitiesExtra(t *testing.T) {
+	data := `{"browserName":"firefox","moz:buildID":"20190101","moz:debuggerAddress":"127.0.0.1:9222","vendor:x":{"a":1}}`
+
+	var c Capabilities
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatalf("cannot unmarshal: %s", err)
+	}
+	if c.BrowserName != "firefox" {
+		t.Errorf("unexpected browser name: %s", c.BrowserName)
+	}
+	if c.DebuggerAddress != "127.0.0.1:9222" {
+		t.Errorf("unexpected debugger address: %s", c.DebuggerAddress)
+	}
+	if l := len(c.Extra); l != 2 {
+		t.Fatalf("expected 2 extra keys, got %+v", c.Extra)
+	}
+	if c.Extra["moz:buildID"] != "20190101" {
+		t.Errorf("unexpected extra: %+v", c.Extra)
+	}
+
+	buf, err := json.Marshal(&c)
+	if err != nil {
+		t.Fatalf("cannot marshal: %s", err)
+	}
+	if str := string(buf); str != data {
+		t.Fatalf("unexpected result: %s", str)
+	}
+}
+
+func TestCapabilitiesDebugger(t *testing.T) {
+	c := &Capabilities{EnableDebugger: true}
+	buf, _ := json.Marshal(c)
+	if str := string(buf); str != `{"moz:debuggerAddress":true}` {
+		t.Fatalf("unexpected result: %s", str)
+	}
+
+	c = &Capabilities{SetWindowRect: Bool(false)}
+	buf, _ = json.Marshal(c)
+	if str := string(buf); str != `{"setWindowRect":false}` {
+		t.Fatalf("unexpected result: %s", str)
+	}
+}
+
+func TestCapabilitiesValidate(t *testing.T) {
+	cases := map[string]*Capabilities{
+		"pageLoadStrategy":        {PageLoadStrategy: "fast"},
+		"unhandledPromptBehavior": {UnhandledPromptBehavior: "close"},
+		"timeouts":                {Timeouts: &Timeouts{Script: -1}},
+		"proxy":                   {Proxy: &Proxy{Type: "socks"}},
+		"moz:firefoxOptions": {FirefoxOptions: &FirefoxOptions{
+			Log: &FirefoxLog{Level: "verbose"},
+		}},
+		"custom": {Extra: map[string]interface{}{"custom": 1}},
+	}
+
+	for key, c := range cases {
+		t.Run(key, func(t *testing.T) {
+			err := c.Validate()
+			e, ok := err.(*ErrCapability)
+			if !ok {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e.Key != key {
+				t.Fatalf("unexpected key: %s", e.Key)
+			}
+		})
+	}
+
+	ok := &Capabilities{
+		PageLoadStrategy:        PageLoadEager,
+		UnhandledPromptBehavior: PromptAcceptAndNotify,
+		FirefoxOptions: &FirefoxOptions{
+			Args:  []string{"-headless"},
+			Log:   &FirefoxLog{Level: "trace"},
+			Prefs: map[string]interface{}{"dom.ipc.processCount": 1},
+		},
+		Extra: map[string]interface{}{"vendor:x": true},
+	}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestCapabilitiesRequest(t *testing.T) {
+	req := &CapabilitiesRequest{
+		AlwaysMatch: &Capabilities{AcceptInsecureCerts: true},
+		FirstMatch: []*Capabilities{
+			{PageLoadStrategy: PageLoadEager},
+			{SetWindowRect: Bool(true)},
+			{StrictFileInteractability: Bool(false)},
+		},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	list, err := req.Merge()
+	if err != nil {
+		t.Fatalf("cannot merge: %s", err)
+	}
+	if l := len(list); l != 3 {
+		t.Fatalf("expected 3 candidates, got %d", l)
+	}
+	if !list[0].AcceptInsecureCerts || list[0].PageLoadStrategy != PageLoadEager {
+		t.Errorf("unexpected 1st candidate: %+v", list[0])
+	}
+	if !list[1].AcceptInsecureCerts || list[1].SetWindowRect == nil || !*list[1].SetWindowRect {
+		t.Errorf("unexpected 2nd candidate: %+v", list[1])
+	}
+	if p := list[2].StrictFileInteractability; p == nil || *p {
+		t.Errorf("unexpected 3rd candidate: %+v", list[2])
+	}
+
+	req.FirstMatch = append(req.FirstMatch, &Capabilities{AcceptInsecureCerts: true})
+	err = req.Validate()
+	if e, ok := err.(*ErrCapability); !ok || e.Key != "acceptInsecureCerts" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list, _ = (&CapabilitiesRequest{}).Merge()
+	if len(list) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(list))
+	}
+}
@@ -90,3 +90,13 @@ func (e *ErrConnection) Error() (ret string) {
 func (e *ErrConnection) String() (ret string) {
 	return "connection error when " + e.When + ": " + e.Origin.Error()
 }
+
+// ErrCapability denotes an invalid capability is found when validating
+type ErrCapability struct {
+	Key    string
+	Reason string
+}
+
+func (e *ErrCapability) Error() (ret string) {
+	return "invalid capability " + e.Key + ": " + e.Reason
+}
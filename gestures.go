@@ -0,0 +1,108 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+const (
+	// ids of touch input sources used by gesture helpers
+	Finger1Source = "finger1"
+	Finger2Source = "finger2"
+)
+
+// distance between fingers in TwoFingerScroll
+const fingerGap = 40
+
+// Finger retrieves (or creates) a touch input source
+//
+// Touch contacts are always reported as primary button, so use
+// Down(MouseLeft) and Up(MouseLeft) with it.
+func (b *ActionChain) Finger(id string) (ret *PointerSource) {
+	return b.Pointer(id, PointerTouch)
+}
+
+// Tap touches (x, y) of viewport with one finger
+func (b *ActionChain) Tap(x, y int) (z *ActionChain) {
+	b.Finger(Finger1Source).
+		MoveTo(x, y, 0).
+		Down(MouseLeft).
+		Up(MouseLeft)
+	return b
+}
+
+// LongPress touches (x, y) of viewport for ms milliseconds
+func (b *ActionChain) LongPress(x, y, ms int) (z *ActionChain) {
+	b.Finger(Finger1Source).
+		MoveTo(x, y, 0).
+		Down(MouseLeft).
+		Pause(ms).
+		Up(MouseLeft)
+	return b
+}
+
+// Swipe drags one finger from (x1, y1) to (x2, y2) in duration milliseconds
+func (b *ActionChain) Swipe(x1, y1, x2, y2, duration int) (z *ActionChain) {
+	b.Finger(Finger1Source).
+		MoveTo(x1, y1, 0).
+		Down(MouseLeft).
+		MoveTo(x2, y2, duration).
+		Up(MouseLeft)
+	return b
+}
+
+// twoFingers moves two fingers from (ax1, ay1), (bx1, by1) to (ax2, ay2),
+// (bx2, by2) in parallel
+func (b *ActionChain) twoFingers(
+	ax1, ay1, bx1, by1, ax2, ay2, bx2, by2, duration int,
+) (z *ActionChain) {
+	f1 := b.Finger(Finger1Source)
+	f2 := b.Finger(Finger2Source)
+
+	b.Together(func() {
+		f1.MoveTo(ax1, ay1, 0)
+		f2.MoveTo(bx1, by1, 0)
+	})
+	b.Together(func() {
+		f1.Down(MouseLeft)
+		f2.Down(MouseLeft)
+	})
+	b.Together(func() {
+		f1.MoveTo(ax2, ay2, duration)
+		f2.MoveTo(bx2, by2, duration)
+	})
+	b.Together(func() {
+		f1.Up(MouseLeft)
+		f2.Up(MouseLeft)
+	})
+	return b
+}
+
+// Pinch moves two fingers horizontally around (x, y) of viewport
+//
+// Distance between fingers changes from "from" to "to" in duration
+// milliseconds. It zooms in if to > from, zooms out otherwise.
+func (b *ActionChain) Pinch(x, y, from, to, duration int) (z *ActionChain) {
+	return b.twoFingers(
+		x-from/2, y, x+from/2, y,
+		x-to/2, y, x+to/2, y,
+		duration,
+	)
+}
+
+// TwoFingerScroll drags two fingers side by side from (x, y) of viewport by
+// (dx, dy) in duration milliseconds
+func (b *ActionChain) TwoFingerScroll(x, y, dx, dy, duration int) (z *ActionChain) {
+	half := fingerGap / 2
+	return b.twoFingers(
+		x-half, y, x+half, y,
+		x-half+dx, y+dy, x+half+dx, y+dy,
+		duration,
+	)
+}
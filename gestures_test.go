@@ -0,0 +1,84 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import "testing"
+
+func TestPointerProperties(t *testing.T) {
+	var chain ActionChain
+	pen := chain.Pointer("pen", PointerPen)
+	pen.MoveTo(1, 2, 0)
+	pen.With(PointerProperties{Pressure: 0.5, TiltX: -30, Width: 2}).
+		Down(MouseLeft)
+
+	testActionJSON(t, chain, `[{"actions":[`+
+		`{"duration":0,"origin":"viewport","type":"pointerMove","x":1,"y":2},`+
+		`{"button":0,"pressure":0.5,"tiltX":-30,"type":"pointerDown","width":2}`+
+		`],"id":"pen","parameters":{"pointerType":"pen"},"type":"pointer"}]`)
+}
+
+func TestGestureTap(t *testing.T) {
+	var chain ActionChain
+	chain.Tap(5, 6)
+
+	testActionJSON(t, chain, `[{"actions":[`+
+		`{"duration":0,"origin":"viewport","type":"pointerMove","x":5,"y":6},`+
+		jsonDown+`,`+jsonUp+
+		`],"id":"finger1","parameters":{"pointerType":"touch"},"type":"pointer"}]`)
+}
+
+func TestGestureLongPress(t *testing.T) {
+	var chain ActionChain
+	chain.LongPress(5, 6, 500)
+
+	testActionJSON(t, chain, `[{"actions":[`+
+		`{"duration":0,"origin":"viewport","type":"pointerMove","x":5,"y":6},`+
+		jsonDown+`,{"duration":500,"type":"pause"},`+jsonUp+
+		`],"id":"finger1","parameters":{"pointerType":"touch"},"type":"pointer"}]`)
+}
+
+func TestGesturePinch(t *testing.T) {
+	var chain ActionChain
+	chain.Pinch(100, 50, 20, 60, 300)
+
+	move := func(x, dur string) string {
+		return `{"duration":` + dur + `,"origin":"viewport","type":"pointerMove","x":` + x + `,"y":50}`
+	}
+	testActionJSON(t, chain, `[`+
+		`{"actions":[`+move("90", "0")+`,`+jsonDown+`,`+move("70", "300")+`,`+jsonUp+
+		`],"id":"finger1","parameters":{"pointerType":"touch"},"type":"pointer"},`+
+		`{"actions":[`+move("110", "0")+`,`+jsonDown+`,`+move("130", "300")+`,`+jsonUp+
+		`],"id":"finger2","parameters":{"pointerType":"touch"},"type":"pointer"}`+
+		`]`)
+}
+
+func TestGestureSequential(t *testing.T) {
+	var chain ActionChain
+	chain.Tap(1, 1).TwoFingerScroll(100, 100, 0, -50, 200).Swipe(0, 0, 10, 0, 100)
+
+	f1 := chain.Source(Finger1Source)
+	f2 := chain.Source(Finger2Source)
+	// tap: 3 ticks, scroll: 4 ticks, swipe: 4 ticks
+	if l := len(f1.Actions); l != 11 {
+		t.Fatalf("unexpected ticks of finger1: %d", l)
+	}
+	// padded with pauses for tap, not padded after scroll
+	if l := len(f2.Actions); l != 7 {
+		t.Fatalf("unexpected ticks of finger2: %d", l)
+	}
+	if a := f2.Actions[2]; a.Type != PauseAction {
+		t.Fatalf("expected pause, got %+v", a)
+	}
+	if a := f2.Actions[5]; a.Type != PointerMoveAction || a.X != 120 || a.Y != 50 {
+		t.Fatalf("unexpected move of finger2: %+v", a)
+	}
+}
@@ -10,3 +10,5 @@
 // See License.txt for further information.
 
 module github.com/raohwork/marionette-go
+
+go 1.18
@@ -0,0 +1,238 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Point is a position in viewport
+type Point struct {
+	X int
+	Y int
+}
+
+// PathOptions controls paths generated by HumanPath
+//
+// Zero-valued fields use default values.
+type PathOptions struct {
+	// number of pointerMove actions, default to 1 per 10px (5 to 100)
+	Steps int
+	// total duration in milliseconds, default to 300
+	Duration int
+	// max deviation from straight line, relative to distance, default to 0.15
+	// set to negative value to disable
+	Curvature float64
+	// max random offset in pixels of each intermediate point, default to 1
+	// set to negative value to disable
+	Jitter float64
+	// maps progress of time (0 to 1) to progress of path, default to EaseInOut
+	Easing func(t float64) float64
+	// random source, for reproducible paths
+	Rand *rand.Rand
+	// size of viewport, points are clamped into it. Zero value means unknown,
+	// points are only clamped to non-negative coordinates
+	Bounds Point
+}
+
+// EaseInOut is a smoothstep easing: slow at both ends, fast in the middle
+func EaseInOut(t float64) float64 {
+	return t * t * (3 - 2*t)
+}
+
+// Linear is a linear easing, which moves at constant speed
+func Linear(t float64) float64 {
+	return t
+}
+
+func (o *PathOptions) normalize(dist float64) (ret PathOptions) {
+	if o != nil {
+		ret = *o
+	}
+	if ret.Steps <= 0 {
+		ret.Steps = int(dist / 10)
+		if ret.Steps < 5 {
+			ret.Steps = 5
+		}
+		if ret.Steps > 100 {
+			ret.Steps = 100
+		}
+	}
+	if ret.Duration <= 0 {
+		ret.Duration = 300
+	}
+	if ret.Curvature == 0 {
+		ret.Curvature = 0.15
+	}
+	if ret.Curvature < 0 {
+		ret.Curvature = 0
+	}
+	if ret.Jitter == 0 {
+		ret.Jitter = 1
+	}
+	if ret.Jitter < 0 {
+		ret.Jitter = 0
+	}
+	if ret.Easing == nil {
+		ret.Easing = EaseInOut
+	}
+	if ret.Rand == nil {
+		ret.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return
+}
+
+// HumanPath generates a curved path from "from" to "to"
+//
+// The path is a cubic bezier curve with random control points, sampled with
+// easing and jittered. Returned points do not include "from", and the last one
+// is exactly "to". Points are clamped into viewport (see PathOptions.Bounds),
+// since moving out of viewport is rejected by browser. Durations of each step
+// are also returned, which add up to opts.Duration.
+func HumanPath(from, to Point, opts *PathOptions) (points []Point, durations []int) {
+	dx, dy := float64(to.X-from.X), float64(to.Y-from.Y)
+	dist := math.Hypot(dx, dy)
+	o := opts.normalize(dist)
+
+	// unit normal of the line
+	nx, ny := 0.0, 0.0
+	if dist > 0 {
+		nx, ny = -dy/dist, dx/dist
+	}
+	ctrl := func(at float64) (x, y float64) {
+		off := (o.Rand.Float64()*2 - 1) * o.Curvature * dist
+		return float64(from.X) + dx*at + nx*off, float64(from.Y) + dy*at + ny*off
+	}
+	// control points at 1/3 and 2/3 keep the curve a straight line with
+	// constant speed when curvature is disabled
+	c1x, c1y := ctrl(1.0 / 3)
+	c2x, c2y := ctrl(2.0 / 3)
+
+	bezier := func(t float64) (x, y float64) {
+		u := 1 - t
+		a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+		x = a*float64(from.X) + b*c1x + c*c2x + d*float64(to.X)
+		y = a*float64(from.Y) + b*c1y + c*c2y + d*float64(to.Y)
+		return
+	}
+
+	points = make([]Point, 0, o.Steps)
+	durations = make([]int, 0, o.Steps)
+	elapsed := 0
+	for i := 1; i <= o.Steps; i++ {
+		p := to
+		if i < o.Steps {
+			x, y := bezier(o.Easing(float64(i) / float64(o.Steps)))
+			x += (o.Rand.Float64()*2 - 1) * o.Jitter
+			y += (o.Rand.Float64()*2 - 1) * o.Jitter
+			p = Point{
+				X: clamp(int(math.Round(x)), o.Bounds.X),
+				Y: clamp(int(math.Round(y)), o.Bounds.Y),
+			}
+		}
+		points = append(points, p)
+
+		// split duration evenly, rounding error goes to last step
+		d := o.Duration * i / o.Steps
+		durations = append(durations, d-elapsed)
+		elapsed = d
+	}
+
+	return
+}
+
+// clamp limits v into [0, size), size <= 0 means unbounded
+func clamp(v, size int) (ret int) {
+	if size > 0 && v >= size {
+		v = size - 1
+	}
+	if v < 0 {
+		v = 0
+	}
+	return v
+}
+
+// ErrUnknownPosition denotes the start point of HumanMoveTo cannot be determined
+var ErrUnknownPosition = errors.New("marionette: position of pointer is unknown, use HumanMove instead")
+
+// position computes current position of the pointer source
+//
+// It returns false if the position cannot be determined, like at the beginning
+// of the chain or after moving relative to an element.
+func (s *PointerSource) position() (ret Point, ok bool) {
+	for _, a := range s.seq.Actions {
+		if a.Type != PointerMoveAction {
+			continue
+		}
+		switch {
+		case a.OriginElement != nil:
+			ok = false
+		case a.Origin == "pointer":
+			ret.X += a.X
+			ret.Y += a.Y
+		default:
+			ret, ok = Point{X: a.X, Y: a.Y}, true
+		}
+	}
+	return
+}
+
+// HumanMoveTo moves the pointer to (x, y) of viewport along a HumanPath
+//
+// The starting point is computed from previous moves in this chain. If it cannot
+// be determined, ErrUnknownPosition is recorded in the chain (see
+// ActionChain.Err) and nothing is added, use HumanMove to specify it.
+func (s *PointerSource) HumanMoveTo(x, y int, opts *PathOptions) (z *PointerSource) {
+	from, ok := s.position()
+	if !ok {
+		s.chain.fail(ErrUnknownPosition)
+		return s
+	}
+	return s.HumanMove(from, Point{X: x, Y: y}, opts)
+}
+
+// HumanMove moves the pointer from "from" to "to" along a HumanPath
+//
+// The pointer is moved to "from" directly if it is not there.
+func (s *PointerSource) HumanMove(from, to Point, opts *PathOptions) (z *PointerSource) {
+	if cur, ok := s.position(); !ok || cur != from {
+		s.MoveTo(from.X, from.Y, 0)
+	}
+
+	points, durations := HumanPath(from, to, opts)
+	for i, p := range points {
+		s.MoveTo(p.X, p.Y, durations[i])
+	}
+	return s
+}
+
+// HumanMoveThrough moves the pointer along points, each segment is a HumanPath
+//
+// Useful for drawing on canvas:
+//
+//	pen.MoveTo(pts[0].X, pts[0].Y, 0).Down(marionette.MouseLeft)
+//	pen.HumanMoveThrough(pts[1:], nil).Up(marionette.MouseLeft)
+func (s *PointerSource) HumanMoveThrough(points []Point, opts *PathOptions) (z *PointerSource) {
+	for _, p := range points {
+		s.HumanMoveTo(p.X, p.Y, opts)
+	}
+	return s
+}
+
+// MouseHumanMoveTo is like PointerSource.HumanMoveTo, using default mouse source
+func (b *ActionChain) MouseHumanMoveTo(x, y int, opts *PathOptions) (z *ActionChain) {
+	b.Pointer(DefaultMouseSource, PointerMouse).HumanMoveTo(x, y, opts)
+	return b
+}
@@ -0,0 +1,147 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestHumanPath(t *testing.T) {
+	from, to := Point{X: 0, Y: 0}, Point{X: 300, Y: 100}
+	opts := &PathOptions{Duration: 500, Rand: rand.New(rand.NewSource(1))}
+	points, durations := HumanPath(from, to, opts)
+
+	// 316px => 31 steps
+	if len(points) != 31 || len(durations) != 31 {
+		t.Fatalf("unexpected steps: %d, %d", len(points), len(durations))
+	}
+	if points[len(points)-1] != to {
+		t.Fatalf("path does not end at destination: %+v", points[len(points)-1])
+	}
+
+	sum := 0
+	for _, d := range durations {
+		sum += d
+	}
+	if sum != 500 {
+		t.Fatalf("durations add up to %d", sum)
+	}
+
+	straight := true
+	for _, p := range points {
+		// 0.15 curvature + jitter
+		if p.X < -50 || p.X > 350 || p.Y < -50 || p.Y > 150 {
+			t.Fatalf("point out of range: %+v", p)
+		}
+		if p.Y*3 != p.X {
+			straight = false
+		}
+	}
+	if straight {
+		t.Fatal("path should not be straight")
+	}
+
+	// same seed, same path
+	again, _ := HumanPath(from, to, &PathOptions{Duration: 500, Rand: rand.New(rand.NewSource(1))})
+	for i := range again {
+		if again[i] != points[i] {
+			t.Fatalf("path is not reproducible at #%d", i)
+		}
+	}
+}
+
+func TestHumanPathEdge(t *testing.T) {
+	// along top edge of viewport
+	from, to := Point{X: 0, Y: 0}, Point{X: 500, Y: 0}
+	for seed := int64(0); seed < 50; seed++ {
+		opts := &PathOptions{Jitter: 5, Rand: rand.New(rand.NewSource(seed))}
+		points, _ := HumanPath(from, to, opts)
+		for _, p := range points {
+			if p.X < 0 || p.Y < 0 {
+				t.Fatalf("point out of viewport with seed %d: %+v", seed, p)
+			}
+		}
+	}
+}
+
+func TestHumanPathBounds(t *testing.T) {
+	// along bottom-right corner of 100x50 viewport
+	bounds := Point{X: 100, Y: 50}
+	for seed := int64(0); seed < 50; seed++ {
+		opts := &PathOptions{Jitter: 5, Bounds: bounds, Rand: rand.New(rand.NewSource(seed))}
+		points, _ := HumanPath(Point{X: 99, Y: 0}, Point{X: 99, Y: 49}, opts)
+		for _, p := range points {
+			if p.X < 0 || p.Y < 0 || p.X >= bounds.X || p.Y >= bounds.Y {
+				t.Fatalf("point out of viewport with seed %d: %+v", seed, p)
+			}
+		}
+	}
+}
+
+func TestHumanPathStraight(t *testing.T) {
+	opts := &PathOptions{Steps: 4, Duration: 10, Curvature: -1, Jitter: -1, Easing: Linear}
+	points, durations := HumanPath(Point{}, Point{X: 40, Y: 80}, opts)
+
+	expected := []Point{{10, 20}, {20, 40}, {30, 60}, {40, 80}}
+	for i, p := range points {
+		if p != expected[i] {
+			t.Fatalf("unexpected point #%d: %+v", i, p)
+		}
+	}
+	if durations[0] != 2 || durations[1] != 3 || durations[2] != 2 || durations[3] != 3 {
+		t.Fatalf("unexpected durations: %v", durations)
+	}
+}
+
+func TestHumanMoveTo(t *testing.T) {
+	var chain ActionChain
+	mouse := chain.Pointer("mouse", PointerMouse)
+	opts := &PathOptions{Steps: 2, Curvature: -1, Jitter: -1, Easing: Linear}
+	mouse.MoveTo(10, 10, 0).MoveBy(10, 0, 0)
+	mouse.HumanMoveTo(40, 10, opts)
+
+	acts := chain.Source("mouse").Actions
+	if len(acts) != 4 {
+		t.Fatalf("unexpected actions: %d", len(acts))
+	}
+	if a := acts[2]; a.X != 30 || a.Y != 10 || a.Origin != "viewport" || a.Duration != 150 {
+		t.Fatalf("unexpected move: %+v", a)
+	}
+
+	// unknown position, moves to start point first
+	mouse.MoveToElement(&WebElement{}, 0, 0, 0)
+	mouse.HumanMove(Point{X: 0, Y: 0}, Point{X: 10, Y: 0}, opts)
+	if l := len(chain.Source("mouse").Actions); l != 8 {
+		t.Fatalf("unexpected actions: %d", l)
+	}
+	if chain.Err() != nil {
+		t.Fatalf("unexpected error: %s", chain.Err())
+	}
+
+	// unknown position, nothing is added
+	mouse.MoveToElement(&WebElement{}, 0, 0, 0)
+	mouse.HumanMoveTo(10, 0, opts)
+	if l := len(chain.Source("mouse").Actions); l != 9 {
+		t.Fatalf("unexpected actions: %d", l)
+	}
+	if chain.Err() != ErrUnknownPosition {
+		t.Fatalf("unexpected error: %v", chain.Err())
+	}
+
+	// beginning of the chain
+	var fresh ActionChain
+	fresh.MouseHumanMoveTo(10, 10, opts)
+	if fresh.Err() != ErrUnknownPosition || len(fresh.Source(DefaultMouseSource).Actions) != 0 {
+		t.Fatalf("unexpected result at beginning of chain: %v", fresh.Err())
+	}
+}
@@ -0,0 +1,256 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WebDriver key codepoints, used in KeyDown/KeyUp and SendKeys
+//
+// See https://w3c.github.io/webdriver/#keyboard-actions
+const (
+	KeyUnidentified = "\uE000"
+	KeyCancel       = "\uE001"
+	KeyHelp         = "\uE002"
+	KeyBackspace    = "\uE003"
+	KeyTab          = "\uE004"
+	KeyClear        = "\uE005"
+	KeyReturn       = "\uE006"
+	KeyEnter        = "\uE007"
+	KeyShift        = "\uE008"
+	KeyControl      = "\uE009"
+	KeyAlt          = "\uE00A"
+	KeyPause        = "\uE00B"
+	KeyEscape       = "\uE00C"
+	KeySpace        = "\uE00D"
+	KeyPageUp       = "\uE00E"
+	KeyPageDown     = "\uE00F"
+	KeyEnd          = "\uE010"
+	KeyHome         = "\uE011"
+	KeyArrowLeft    = "\uE012"
+	KeyArrowUp      = "\uE013"
+	KeyArrowRight   = "\uE014"
+	KeyArrowDown    = "\uE015"
+	KeyInsert       = "\uE016"
+	KeyDelete       = "\uE017"
+	KeySemicolon    = "\uE018"
+	KeyEquals       = "\uE019"
+
+	KeyNumpad0         = "\uE01A"
+	KeyNumpad1         = "\uE01B"
+	KeyNumpad2         = "\uE01C"
+	KeyNumpad3         = "\uE01D"
+	KeyNumpad4         = "\uE01E"
+	KeyNumpad5         = "\uE01F"
+	KeyNumpad6         = "\uE020"
+	KeyNumpad7         = "\uE021"
+	KeyNumpad8         = "\uE022"
+	KeyNumpad9         = "\uE023"
+	KeyNumpadMultiply  = "\uE024"
+	KeyNumpadAdd       = "\uE025"
+	KeyNumpadSeparator = "\uE026"
+	KeyNumpadSubtract  = "\uE027"
+	KeyNumpadDecimal   = "\uE028"
+	KeyNumpadDivide    = "\uE029"
+
+	KeyF1  = "\uE031"
+	KeyF2  = "\uE032"
+	KeyF3  = "\uE033"
+	KeyF4  = "\uE034"
+	KeyF5  = "\uE035"
+	KeyF6  = "\uE036"
+	KeyF7  = "\uE037"
+	KeyF8  = "\uE038"
+	KeyF9  = "\uE039"
+	KeyF10 = "\uE03A"
+	KeyF11 = "\uE03B"
+	KeyF12 = "\uE03C"
+
+	KeyMeta           = "\uE03D"
+	KeyZenkakuHankaku = "\uE040"
+
+	KeyRightShift   = "\uE050"
+	KeyRightControl = "\uE051"
+	KeyRightAlt     = "\uE052"
+	KeyRightMeta    = "\uE053"
+
+	KeyNumpadPageUp     = "\uE054"
+	KeyNumpadPageDown   = "\uE055"
+	KeyNumpadEnd        = "\uE056"
+	KeyNumpadHome       = "\uE057"
+	KeyNumpadArrowLeft  = "\uE058"
+	KeyNumpadArrowUp    = "\uE059"
+	KeyNumpadArrowRight = "\uE05A"
+	KeyNumpadArrowDown  = "\uE05B"
+	KeyNumpadInsert     = "\uE05C"
+	KeyNumpadDelete     = "\uE05D"
+)
+
+// KeyAccel is the "Accel" modifier used by Shortcut
+//
+// It is KeyMeta on macOS and KeyControl elsewhere, detected by runtime.GOOS. Set
+// it with AccelFor(caps.PlatformName) if the browser runs on another machine.
+var KeyAccel = AccelFor(runtime.GOOS)
+
+// AccelFor returns the "Accel" modifier of the platform
+//
+// platform can be runtime.GOOS or platformName in Capabilities.
+func AccelFor(platform string) (ret string) {
+	switch strings.ToLower(platform) {
+	case "darwin", "mac", "macos":
+		return KeyMeta
+	}
+	return KeyControl
+}
+
+// KeyNames maps case-insensitive key names to keys, used by ParseShortcut
+//
+// "accel" is not here as it depends on KeyAccel.
+var KeyNames = map[string]string{
+	"ctrl":       KeyControl,
+	"control":    KeyControl,
+	"shift":      KeyShift,
+	"alt":        KeyAlt,
+	"option":     KeyAlt,
+	"meta":       KeyMeta,
+	"cmd":        KeyMeta,
+	"command":    KeyMeta,
+	"super":      KeyMeta,
+	"win":        KeyMeta,
+	"enter":      KeyEnter,
+	"return":     KeyReturn,
+	"tab":        KeyTab,
+	"esc":        KeyEscape,
+	"escape":     KeyEscape,
+	"space":      KeySpace,
+	"backspace":  KeyBackspace,
+	"delete":     KeyDelete,
+	"del":        KeyDelete,
+	"insert":     KeyInsert,
+	"ins":        KeyInsert,
+	"home":       KeyHome,
+	"end":        KeyEnd,
+	"pageup":     KeyPageUp,
+	"pagedown":   KeyPageDown,
+	"left":       KeyArrowLeft,
+	"up":         KeyArrowUp,
+	"right":      KeyArrowRight,
+	"down":       KeyArrowDown,
+	"arrowleft":  KeyArrowLeft,
+	"arrowup":    KeyArrowUp,
+	"arrowright": KeyArrowRight,
+	"arrowdown":  KeyArrowDown,
+	"pause":      KeyPause,
+	"help":       KeyHelp,
+	"plus":       "+",
+	"f1":         KeyF1,
+	"f2":         KeyF2,
+	"f3":         KeyF3,
+	"f4":         KeyF4,
+	"f5":         KeyF5,
+	"f6":         KeyF6,
+	"f7":         KeyF7,
+	"f8":         KeyF8,
+	"f9":         KeyF9,
+	"f10":        KeyF10,
+	"f11":        KeyF11,
+	"f12":        KeyF12,
+}
+
+// ParseShortcut parses key combination like "Ctrl+Shift+K" into keys
+//
+// Names are looked up in KeyNames case-insensitively, "Accel" is KeyAccel, and
+// single characters are used as-is. Use "Plus" or a trailing "++" for the plus
+// key, like "Ctrl++".
+func ParseShortcut(combo string) (ret []string, err error) {
+	parts := strings.Split(combo, "+")
+	if strings.HasSuffix(combo, "++") {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		name := strings.ToLower(p)
+		switch {
+		case name == "accel":
+			ret = append(ret, KeyAccel)
+		case KeyNames[name] != "":
+			ret = append(ret, KeyNames[name])
+		case p != "" && utf8.RuneCountInString(p) == 1:
+			ret = append(ret, p)
+		default:
+			return nil, errors.New(
+				"marionette: unknown key " + strconv.Quote(p) +
+					" in shortcut " + strconv.Quote(combo),
+			)
+		}
+	}
+	return
+}
+
+// Type presses and releases every character in text
+//
+// Text is split by code points, so characters outside BMP (which are surrogate
+// pairs in JavaScript) are sent as single key. "\n" and "\t" are sent as
+// KeyEnter and KeyTab.
+func (s *KeySource) Type(text string) (z *KeySource) {
+	for _, r := range text {
+		key := string(r)
+		switch r {
+		case '\n':
+			key = KeyEnter
+		case '\t':
+			key = KeyTab
+		case '\r', utf8.RuneError:
+			continue
+		}
+		s.Press(key)
+	}
+	return s
+}
+
+// Combo presses keys in order and releases them in reverse order
+func (s *KeySource) Combo(keys ...string) (z *KeySource) {
+	for _, k := range keys {
+		s.Down(k)
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		s.Up(keys[i])
+	}
+	return s
+}
+
+// Shortcut parses combo with ParseShortcut and presses it with Combo
+func (s *KeySource) Shortcut(combo string) (z *KeySource, err error) {
+	keys, err := ParseShortcut(combo)
+	if err != nil {
+		return s, err
+	}
+	return s.Combo(keys...), nil
+}
+
+// Type is like KeySource.Type, using default key source
+func (b *ActionChain) Type(text string) (z *ActionChain) {
+	b.Keyboard(DefaultKeySource).Type(text)
+	return b
+}
+
+// Shortcut is like KeySource.Shortcut, using default key source
+func (b *ActionChain) Shortcut(combo string) (z *ActionChain, err error) {
+	_, err = b.Keyboard(DefaultKeySource).Shortcut(combo)
+	return b, err
+}
@@ -0,0 +1,97 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseShortcut(t *testing.T) {
+	accel := KeyAccel
+	defer func() { KeyAccel = accel }()
+	KeyAccel = AccelFor("mac")
+
+	cases := map[string][]string{
+		"Ctrl+Shift+K": {KeyControl, KeyShift, "K"},
+		"accel+s":      {KeyMeta, "s"},
+		"Ctrl++":       {KeyControl, "+"},
+		"Alt + F4":     {KeyAlt, KeyF4},
+		"Shift+Plus":   {KeyShift, "+"},
+		"é":            {"é"},
+	}
+	for combo, expected := range cases {
+		keys, err := ParseShortcut(combo)
+		if err != nil {
+			t.Errorf("cannot parse %s: %s", combo, err)
+			continue
+		}
+		if !reflect.DeepEqual(keys, expected) {
+			t.Errorf("unexpected result of %s: %q", combo, keys)
+		}
+	}
+
+	for _, combo := range []string{"", "Ctrl+", "Hyper+K", "Ctrl+ab"} {
+		if _, err := ParseShortcut(combo); err == nil {
+			t.Errorf("expected error for %q", combo)
+		}
+	}
+}
+
+func TestAccelFor(t *testing.T) {
+	if AccelFor("darwin") != KeyMeta || AccelFor("linux") != KeyControl ||
+		AccelFor("windows") != KeyControl || AccelFor("mac") != KeyMeta {
+		t.Fatal("unexpected accel key")
+	}
+}
+
+func keysOf(seq *ActionSequence) (ret []string) {
+	for _, a := range seq.Actions {
+		ret = append(ret, string(a.Type)+":"+a.Value)
+	}
+	return
+}
+
+func TestShortcut(t *testing.T) {
+	var chain ActionChain
+	if _, err := chain.Shortcut("Ctrl+Shift+K"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{
+		"keyDown:" + KeyControl, "keyDown:" + KeyShift, "keyDown:K",
+		"keyUp:K", "keyUp:" + KeyShift, "keyUp:" + KeyControl,
+	}
+	if keys := keysOf(chain.Source(DefaultKeySource)); !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("unexpected actions: %q", keys)
+	}
+
+	if _, err := chain.Shortcut("Ctrl+Foo"); err == nil {
+		t.Fatal("expected error")
+	}
+	if l := chain.Ticks(); l != 6 {
+		t.Fatalf("invalid shortcut should add nothing, got %d ticks", l)
+	}
+}
+
+func TestType(t *testing.T) {
+	var chain ActionChain
+	chain.Type("a😀\r\n")
+
+	expected := []string{
+		"keyDown:a", "keyUp:a", "keyDown:😀", "keyUp:😀",
+		"keyDown:" + KeyEnter, "keyUp:" + KeyEnter,
+	}
+	if keys := keysOf(chain.Source(DefaultKeySource)); !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("unexpected actions: %q", keys)
+	}
+}
@@ -32,6 +32,31 @@ func (s *Commander) runSync(cmd mncmd.Command) (err error) {
 	return
 }
 
+// runInChrome switches to chrome context, runs f and switches back
+//
+// Context is a state of the session, so commands sent by other goroutines in the
+// meantime also run in chrome context. It cannot cooperate with ContextManager,
+// which tracks current context locally.
+func (s *Commander) runInChrome(f func() error) (err error) {
+	cur, err := s.MozGetContext()
+	if err != nil {
+		return
+	}
+
+	if cur != marionette.ChromeContext {
+		if err = s.MozSetContext(marionette.ChromeContext); err != nil {
+			return
+		}
+		defer func() {
+			if e := s.MozSetContext(cur); err == nil {
+				err = e
+			}
+		}()
+	}
+
+	return f()
+}
+
 // AcceptAlert presses the "OK" button of the modal dialog
 func (s *Commander) AcceptAlert() (err error) {
 	cmd := &mncmd.AcceptAlert{}
@@ -82,6 +107,12 @@ func (s *Commander) DeleteCookie(name string) (err error) {
 	return s.runSync(cmd)
 }
 
+// DeleteSession ends current webdriver session
+func (s *Commander) DeleteSession() (err error) {
+	cmd := &mncmd.DeleteSession{}
+	return s.runSync(cmd)
+}
+
 // DismissAlert presses "close" button of the modal dialog
 func (s *Commander) DismissAlert() (err error) {
 	cmd := &mncmd.DismissAlert{}
@@ -115,6 +146,8 @@ type ScriptResult struct {
 // ExecuteAsyncScript executes the script in default, mutable sandbox
 //
 // It returns the value passed to callback. Callback is always the last argument.
+// The channel is buffered, so it is safe to abandon it. See EvalAsync for typed
+// version.
 func (s *Commander) ExecuteAsyncScript(script string, args ...interface{}) (
 	ch chan ScriptResult, err error,
 ) {
@@ -128,7 +161,7 @@ func (s *Commander) ExecuteAsyncScript(script string, args ...interface{}) (
 		return
 	}
 
-	ch = make(chan ScriptResult)
+	ch = make(chan ScriptResult, 1)
 	go func() {
 		defer close(ch)
 		var data interface{}
@@ -146,7 +179,8 @@ func (s *Commander) ExecuteAsyncScript(script string, args ...interface{}) (
 //
 // It returns the value passed to callback. Callback is always the last argument.
 //
-// The sandbox is cached on window object for later use
+// The sandbox is cached on window object for later use. The channel is buffered,
+// so it is safe to abandon it. See EvalAsyncIn for typed version.
 func (s *Commander) ExecuteAsyncScriptIn(
 	sandbox, script string, args ...interface{},
 ) (
@@ -164,7 +198,7 @@ func (s *Commander) ExecuteAsyncScriptIn(
 		return
 	}
 
-	ch = make(chan ScriptResult)
+	ch = make(chan ScriptResult, 1)
 	go func() {
 		defer close(ch)
 		var data interface{}
@@ -213,6 +247,8 @@ type ElementResult struct {
 }
 
 // FindElementAsync finds an element asynchronously
+//
+// See FindElementFuture for typed version.
 func (s *Commander) FindElementAsync(
 	by marionette.FindStrategy, qstr string, root *marionette.WebElement,
 ) (ch chan ElementResult, err error) {
@@ -258,7 +294,9 @@ type ElementResults struct {
 	Err    error
 }
 
-// FindElements retrieves all matching elements asynchronously
+// FindElementsAsync retrieves all matching elements asynchronously
+//
+// See FindElementsFuture for typed version.
 func (s *Commander) FindElementsAsync(
 	by marionette.FindStrategy, qstr string, root *marionette.WebElement,
 ) (ch chan ElementResults, err error) {
@@ -634,6 +672,8 @@ func (s *Commander) Navigate(url string) (err error) {
 }
 
 // NavigateAsync runs Navigate command asynchronously
+//
+// See NavigateFuture for typed version.
 func (s *Commander) NavigateAsync(url string) (ch chan error) {
 	cmd := &mncmd.Navigate{URL: url}
 	ch = make(chan error, 1)
@@ -688,6 +728,44 @@ func (s *Commander) NewSessionWith(page string, insecureCert bool) (
 	return cmd.Decode(msg)
 }
 
+// NewSessionWithCaps creates a new webdriver session with full capabilities
+//
+// It validates the request, merges AlwaysMatch with each of FirstMatch and tries
+// them in order until the server accepts one, as "process capabilities" described
+// in WebDriver spec. Error of last attempt is returned if all candidates are
+// rejected.
+func (s *Commander) NewSessionWithCaps(req *marionette.CapabilitiesRequest) (
+	id string, cap *marionette.Capabilities, err error,
+) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	candidates, err := req.Merge()
+	if err != nil {
+		return
+	}
+
+	for _, c := range candidates {
+		cmd := &mncmd.NewSession{Capabilities: c}
+		var msg *marionette.Message
+		msg, err = s.Sync(cmd)
+		if err == nil {
+			return cmd.Decode(msg)
+		}
+
+		e, ok := err.(*marionette.ErrDriver)
+		if !ok {
+			return
+		}
+		if e.Type != marionette.ErrSessionNotCreated &&
+			e.Type != marionette.ErrInvalidArgument {
+			return
+		}
+	}
+
+	return
+}
+
 // NewWindow opens a new window
 //
 // The "typ" can be
@@ -721,6 +799,19 @@ func (s *Commander) SendAlertText(text string) (err error) {
 	return s.runSync(cmd)
 }
 
+// SetPermission sets permission state for the origin of current document
+//
+// The name is permission name defined in Permissions API, like "geolocation" or
+// "notifications". The state can be one of marionette.PermissionGranted,
+// marionette.PermissionDenied and marionette.PermissionPrompt.
+func (s *Commander) SetPermission(name, state string) (err error) {
+	cmd := &mncmd.SetPermission{
+		Descriptor: map[string]interface{}{"name": name},
+		State:      state,
+	}
+	return s.runSync(cmd)
+}
+
 // SetTimeouts sets timeout settings of marionette server
 func (s *Commander) SetTimeouts(t *marionette.Timeouts) (err error) {
 	cmd := &mncmd.SetTimeouts{Timeouts: t}
@@ -847,9 +938,16 @@ func (s *Commander) ScreenshotElementBytes(el *marionette.WebElement) (img []byt
 }
 
 // PerformActionsAsync sends virtual input events to current window asynchronously
+//
+// See PerformActionsFuture for typed version.
 func (s *Commander) PerformActionsAsync(act marionette.ActionChain) (errCh chan error) {
 	cmd := &mncmd.PerformActions{Actions: act}
 	errCh = make(chan error, 1)
+	if err := act.Err(); err != nil {
+		errCh <- err
+		close(errCh)
+		return
+	}
 	ch, err := s.Async(cmd)
 	if err != nil {
 		errCh <- err
@@ -867,7 +965,12 @@ func (s *Commander) PerformActionsAsync(act marionette.ActionChain) (errCh chan
 }
 
 // PerformActions sends virtual input events to current window
+//
+// It returns act.Err() if the chain is invalid.
 func (s *Commander) PerformActions(act marionette.ActionChain) (err error) {
+	if err = act.Err(); err != nil {
+		return
+	}
 	cmd := &mncmd.PerformActions{Actions: act}
 	return s.runSync(cmd)
 }
@@ -1022,3 +1125,63 @@ func (s *Commander) ReftestTeardown() (err error) {
 	cmd := &mncmd.ReftestTeardown{}
 	return s.runSync(cmd)
 }
+
+// AddVirtualAuthenticator creates a virtual WebAuthn authenticator
+//
+// It returns the id of created authenticator, which is required by other WebAuthn
+// commands.
+func (s *Commander) AddVirtualAuthenticator(
+	opts *marionette.VirtualAuthenticator,
+) (id string, err error) {
+	cmd := &mncmd.AddVirtualAuthenticator{Options: opts}
+	msg, err := s.Sync(cmd)
+	if err != nil {
+		return
+	}
+
+	return cmd.Decode(msg)
+}
+
+// RemoveVirtualAuthenticator removes specified virtual authenticator
+func (s *Commander) RemoveVirtualAuthenticator(id string) (err error) {
+	cmd := &mncmd.RemoveVirtualAuthenticator{ID: id}
+	return s.runSync(cmd)
+}
+
+// AddCredential injects a credential into specified virtual authenticator
+func (s *Commander) AddCredential(id string, c *marionette.Credential) (err error) {
+	cmd := &mncmd.AddCredential{ID: id, Credential: c}
+	return s.runSync(cmd)
+}
+
+// GetCredentials retrieves all credentials stored in the virtual authenticator
+func (s *Commander) GetCredentials(id string) (ret []*marionette.Credential, err error) {
+	cmd := &mncmd.GetCredentials{ID: id}
+	msg, err := s.Sync(cmd)
+	if err != nil {
+		return
+	}
+
+	return cmd.Decode(msg)
+}
+
+// RemoveCredential removes a credential from the virtual authenticator
+func (s *Commander) RemoveCredential(id string, credID []byte) (err error) {
+	cmd := &mncmd.RemoveCredential{
+		ID:           id,
+		CredentialID: base64.RawURLEncoding.EncodeToString(credID),
+	}
+	return s.runSync(cmd)
+}
+
+// RemoveAllCredentials removes all credentials from the virtual authenticator
+func (s *Commander) RemoveAllCredentials(id string) (err error) {
+	cmd := &mncmd.RemoveAllCredentials{ID: id}
+	return s.runSync(cmd)
+}
+
+// SetUserVerified sets the result of user verification of the authenticator
+func (s *Commander) SetUserVerified(id string, verified bool) (err error) {
+	cmd := &mncmd.SetUserVerified{ID: id, Verified: verified}
+	return s.runSync(cmd)
+}
@@ -47,3 +47,175 @@ func (tc *cmdrTestCase) testReleaseActions(t *testing.T) {
 		t.Fatalf("cannot release actions: %s", err)
 	}
 }
+
+func (tc *cmdrTestCase) testPerformActionModifier(t *testing.T) {
+	btn, _ := tc.FindElement(marionette.ID, "run", nil)
+	rect, _ := tc.GetElementRect(btn)
+	err := tc.ExecuteScript(`
+window.ctrlClicked = false;
+document.querySelector('#run').addEventListener('click', e => {
+  window.ctrlClicked = e.ctrlKey;
+});
+`, nil)
+	if err != nil {
+		t.Fatalf("cannot install listener: %s", err)
+	}
+
+	var chain marionette.ActionChain
+	kbd := chain.Keyboard("kbd")
+	mouse := chain.Pointer("mouse", marionette.PointerMouse)
+	mouse.MoveTo(int(rect.X+rect.W/2), int(rect.Y+rect.H/2), 0)
+	kbd.Down(marionette.KeyControl)
+	mouse.Click(marionette.MouseLeft)
+	kbd.Up(marionette.KeyControl)
+	if err := tc.PerformActions(chain); err != nil {
+		t.Fatalf("cannot perform action: %s", err)
+	}
+
+	var ok bool
+	if err := tc.ExecuteScript(`return window.ctrlClicked`, &ok); err != nil {
+		t.Fatalf("cannot get result: %s", err)
+	}
+	if !ok {
+		t.Fatal("ctrl key is not pressed when clicking")
+	}
+}
+
+func (tc *cmdrTestCase) testPerformActionElementOrigin(t *testing.T) {
+	btn, _ := tc.FindElement(marionette.ID, "run", nil)
+	result, _ := tc.FindElement(marionette.ID, "result", nil)
+	if err := tc.ExecuteScript(`arguments[0].innerHTML = ''`, nil, result); err != nil {
+		t.Fatalf("cannot clear result: %s", err)
+	}
+
+	var chain marionette.ActionChain
+	chain.MouseMoveToElement(btn, 0, 0, 0).MouseClick(marionette.MouseLeft)
+	if err := tc.PerformActions(chain); err != nil {
+		t.Fatalf("cannot perform action: %s", err)
+	}
+
+	txt, _ := tc.GetElementText(result)
+	if strings.TrimSpace(txt) != "demo" {
+		t.Fatalf("unexpected value: %s", txt)
+	}
+}
+
+func (tc *cmdrTestCase) testPerformActionScroll(t *testing.T) {
+	err := tc.ExecuteScript(`
+document.body.style.height = '5000px';
+window.scrollTo(0, 0);
+`, nil)
+	if err != nil {
+		t.Fatalf("cannot prepare page: %s", err)
+	}
+
+	var chain marionette.ActionChain
+	chain.ScrollBy(10, 10, 0, 200, 0)
+	if err := tc.PerformActions(chain); err != nil {
+		t.Fatalf("cannot perform action: %s", err)
+	}
+
+	var y float64
+	if err := tc.ExecuteScript(`return window.scrollY`, &y); err != nil {
+		t.Fatalf("cannot get scroll position: %s", err)
+	}
+	if y <= 0 {
+		t.Fatalf("page is not scrolled: %v", y)
+	}
+}
+
+func (tc *cmdrTestCase) testPerformActionTap(t *testing.T) {
+	btn, _ := tc.FindElement(marionette.ID, "run", nil)
+	rect, _ := tc.GetElementRect(btn)
+	err := tc.ExecuteScript(`
+window.touched = false;
+document.querySelector('#run').addEventListener('pointerdown', e => {
+  window.touched = e.pointerType === 'touch';
+});
+`, nil)
+	if err != nil {
+		t.Fatalf("cannot install listener: %s", err)
+	}
+
+	var chain marionette.ActionChain
+	chain.Tap(int(rect.X+rect.W/2), int(rect.Y+rect.H/2))
+	if err := tc.PerformActions(chain); err != nil {
+		t.Fatalf("cannot perform action: %s", err)
+	}
+
+	var ok bool
+	if err := tc.ExecuteScript(`return window.touched`, &ok); err != nil {
+		t.Fatalf("cannot get result: %s", err)
+	}
+	if !ok {
+		t.Fatal("touch event is not received")
+	}
+}
+
+func (tc *cmdrTestCase) testPerformActionType(t *testing.T) {
+	input, _ := tc.FindElement(marionette.ID, "text", nil)
+	if err := tc.ElementClear(input); err != nil {
+		t.Fatalf("cannot clear input: %s", err)
+	}
+	if err := tc.ElementClick(input); err != nil {
+		t.Fatalf("cannot focus input: %s", err)
+	}
+
+	var chain marionette.ActionChain
+	chain.Type("héllo 😀x")
+	if _, err := chain.Shortcut("Shift+ArrowLeft"); err != nil {
+		t.Fatalf("cannot parse shortcut: %s", err)
+	}
+	chain.KeyPress(marionette.KeyBackspace)
+	if err := tc.PerformActions(chain); err != nil {
+		t.Fatalf("cannot perform action: %s", err)
+	}
+
+	val, _ := tc.GetElementPropertyStr(input, "value")
+	if val != "héllo 😀" {
+		t.Fatalf("unexpected value: %q", val)
+	}
+}
+
+func (tc *cmdrTestCase) testPerformActionHumanMove(t *testing.T) {
+	btn, _ := tc.FindElement(marionette.ID, "run", nil)
+	err := tc.ExecuteScript(`
+window.moves = 0;
+window.hovered = false;
+document.addEventListener('mousemove', () => window.moves++);
+document.querySelector('#run').addEventListener('mouseover', () => {
+  window.hovered = true;
+});
+`, nil)
+	if err != nil {
+		t.Fatalf("cannot install listener: %s", err)
+	}
+
+	var chain marionette.ActionChain
+	mouse := chain.Pointer(marionette.DefaultMouseSource, marionette.PointerMouse)
+	mouse.MoveTo(20, 20, 0)
+	opts := &marionette.PathOptions{Steps: 10, Duration: 100}
+	if err := tc.HumanMoveToElement(mouse, btn, 0, 0, opts); err != nil {
+		t.Fatalf("cannot compute path: %s", err)
+	}
+	if err := tc.PerformActions(chain); err != nil {
+		t.Fatalf("cannot perform action: %s", err)
+	}
+
+	var res struct {
+		Moves   int  `json:"moves"`
+		Hovered bool `json:"hovered"`
+	}
+	err = tc.ExecuteScript(
+		`return {moves: window.moves, hovered: window.hovered}`, &res,
+	)
+	if err != nil {
+		t.Fatalf("cannot get result: %s", err)
+	}
+	if !res.Hovered {
+		t.Fatal("pointer does not reach the element")
+	}
+	if res.Moves < 5 {
+		t.Fatalf("expected many mousemove events, got %d", res.Moves)
+	}
+}
@@ -11,7 +11,11 @@
 
 package mnclient
 
-import "testing"
+import (
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
 
 func (tc *cmdrTestCase) testNewSessionWith(t *testing.T) {
 	id, cap, err := tc.NewSessionWith("normal", true)
@@ -127,3 +131,34 @@ func (tc *cmdrTestCase) testGetCurrentURL(t *testing.T) {
 		t.Errorf("unexpected url: %s", uri)
 	}
 }
+
+func (tc *cmdrTestCase) testSession(t *testing.T) {
+	if err := tc.DeleteSession(); err != nil {
+		t.Fatalf("cannot delete session: %s", err)
+	}
+
+	sess, err := StartSession(tc.Commander, &marionette.CapabilitiesRequest{
+		AlwaysMatch: &marionette.Capabilities{AcceptInsecureCerts: true},
+		FirstMatch: []*marionette.Capabilities{
+			{PageLoadStrategy: marionette.PageLoadNormal},
+		},
+	})
+	if err != nil {
+		t.Fatalf("cannot start session: %s", err)
+	}
+	if sess.ID == "" {
+		t.Fatal("empty id")
+	}
+	if !sess.Capabilities.AcceptInsecureCerts {
+		t.Errorf("unexpected capabilities: %+v", sess.Capabilities)
+	}
+
+	if err = sess.Delete(); err != nil {
+		t.Fatalf("cannot delete session: %s", err)
+	}
+
+	// leave a usable session for other tests
+	if _, err = StartSession(tc.Commander, nil); err != nil {
+		t.Fatalf("cannot restart session: %s", err)
+	}
+}
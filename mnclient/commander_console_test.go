@@ -0,0 +1,71 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"testing"
+	"time"
+)
+
+func (tc *cmdrTestCase) testCaptureConsole(t *testing.T) {
+	c, err := tc.CaptureConsole(InitScriptTab, 0)
+	if err != nil {
+		t.Fatalf("cannot capture console: %s", err)
+	}
+	defer c.Stop()
+
+	tc.loadTestHTML("console.html")(t)
+
+	found := map[string]ConsoleEntry{}
+	deadline := time.Now().Add(3 * time.Second)
+	for len(found) < 4 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+		entries, err := c.Drain()
+		if err != nil {
+			t.Fatalf("cannot drain: %s", err)
+		}
+		for _, e := range entries {
+			found[e.Source+"/"+e.Message] = e
+		}
+	}
+
+	log, ok := found[ConsoleAPI+`/hello {"a":1}`]
+	if !ok {
+		t.Fatalf("console.log is not captured: %+v", found)
+	}
+	if log.Level != "log" || log.Line == 0 {
+		t.Errorf("unexpected entry of console.log: %+v", log)
+	}
+	if _, ok = found[ConsoleAPI+"/careful"]; !ok {
+		t.Errorf("console.warn is not captured: %+v", found)
+	}
+
+	var exception, rejection bool
+	for _, e := range found {
+		switch e.Source {
+		case ConsoleException:
+			exception = e.IsError()
+		case ConsoleRejection:
+			rejection = e.IsError()
+		}
+	}
+	if !exception || !rejection {
+		t.Errorf("errors are not captured: %+v", found)
+	}
+
+	if _, err = c.Stop(); err != nil {
+		t.Fatalf("cannot stop: %s", err)
+	}
+	if _, err = c.Drain(); err != ErrCaptureStopped {
+		t.Fatalf("unexpected error after stopped: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) testDragAndDrop(t *testing.T) {
+	item, _ := tc.FindElement(marionette.ID, "item", nil)
+	zone, _ := tc.FindElement(marionette.ID, "zone", nil)
+	reject, _ := tc.FindElement(marionette.ID, "reject", nil)
+	result, _ := tc.FindElement(marionette.ID, "result", nil)
+
+	t.Run("Element", func(t *testing.T) {
+		if err := tc.DragAndDrop(item, zone, &DragOptions{OffsetX: 10}); err != nil {
+			t.Fatalf("cannot drag: %s", err)
+		}
+		if txt, _ := tc.GetElementText(result); txt != "item|" {
+			t.Fatalf("unexpected result: %s", txt)
+		}
+	})
+
+	t.Run("Files", func(t *testing.T) {
+		err := tc.DropFiles(zone, DropFile{
+			Name:    "a.txt",
+			Type:    "text/plain",
+			Content: []byte("hello"),
+		})
+		if err != nil {
+			t.Fatalf("cannot drop files: %s", err)
+		}
+		if txt, _ := tc.GetElementText(result); txt != "|a.txt:5" {
+			t.Fatalf("unexpected result: %s", txt)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		err := tc.DragAndDrop(item, reject, &DragOptions{Mode: DragHTML5})
+		if err != ErrDropRejected {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -12,6 +12,7 @@
 package mnclient
 
 import (
+	"strings"
 	"testing"
 
 	marionette "github.com/raohwork/marionette-go"
@@ -320,3 +321,75 @@ func (tc *cmdrTestCase) testIsElementEnabled(t *testing.T) {
 		}
 	})
 }
+
+func (tc *cmdrTestCase) testElementObject(t *testing.T) {
+	ctrl, err := tc.Find(marionette.ID, "ctrl")
+	if err != nil {
+		t.Fatalf("cannot find #ctrl: %s", err)
+	}
+
+	t.Run("Find", func(t *testing.T) {
+		el, err := ctrl.Find(marionette.ID, "text")
+		if err != nil {
+			t.Fatalf("cannot find #text: %s", err)
+		}
+		val, err := el.Attr("value")
+		if err != nil {
+			t.Fatalf("cannot get attribute: %s", err)
+		}
+		if val != "demo" {
+			t.Fatalf("unexpected value: %s", val)
+		}
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		list, err := ctrl.FindAll(marionette.TagName, "input")
+		if err != nil {
+			t.Fatalf("cannot find inputs: %s", err)
+		}
+		if l := len(list); l != 3 {
+			t.Fatalf("expected 3 elements, got %d", l)
+		}
+	})
+
+	t.Run("Parent", func(t *testing.T) {
+		el, _ := ctrl.Find(marionette.ID, "run")
+		p, err := el.Parent()
+		if err != nil {
+			t.Fatalf("cannot get parent: %s", err)
+		}
+		id, _ := p.Attr("id")
+		if id != "ctrl" {
+			t.Fatalf("unexpected parent: %s", id)
+		}
+	})
+
+	t.Run("Click", func(t *testing.T) {
+		btn, _ := ctrl.Find(marionette.ID, "run")
+		if err := btn.Click(); err != nil {
+			t.Fatalf("cannot click: %s", err)
+		}
+		result, _ := tc.Find(marionette.ID, "result")
+		txt, err := result.Text()
+		if err != nil {
+			t.Fatalf("cannot get text: %s", err)
+		}
+		if strings.TrimSpace(txt) != "demo" {
+			t.Fatalf("unexpected text: %s", txt)
+		}
+	})
+
+	t.Run("States", func(t *testing.T) {
+		el, _ := tc.Find(marionette.ID, "disabled")
+		if ok, _ := el.IsDisplayed(); !ok {
+			t.Error("expected to be displayed")
+		}
+		if ok, _ := el.IsEnabled(); ok {
+			t.Error("expected to be disabled")
+		}
+		chk, _ := tc.Find(marionette.ID, "checked")
+		if ok, _ := chk.IsSelected(); !ok {
+			t.Error("expected to be selected")
+		}
+	})
+}
@@ -0,0 +1,159 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) formEvents(t *testing.T) (ret string) {
+	el, err := tc.FindElement(marionette.ID, "events", nil)
+	if err != nil {
+		t.Fatalf("cannot find event log: %s", err)
+	}
+	ret, _ = tc.GetElementPropertyStr(el, "textContent")
+	return
+}
+
+func (tc *cmdrTestCase) testSelect(t *testing.T) {
+	single, _ := tc.FindElement(marionette.ID, "single", nil)
+	multi, _ := tc.FindElement(marionette.ID, "multi", nil)
+
+	check := func(t *testing.T, el *marionette.WebElement, values ...string) {
+		opts, err := tc.GetSelected(el)
+		if err != nil {
+			t.Fatalf("cannot get selected options: %s", err)
+		}
+		if len(opts) != len(values) {
+			t.Fatalf("unexpected selection: %+v", opts)
+		}
+		for idx, o := range opts {
+			if o.Value != values[idx] {
+				t.Fatalf("unexpected selection: %+v", opts)
+			}
+		}
+	}
+
+	t.Run("ByValue", func(t *testing.T) {
+		if err := tc.SelectByValue(single, "b"); err != nil {
+			t.Fatalf("cannot select: %s", err)
+		}
+		check(t, single, "b")
+	})
+	t.Run("ByText", func(t *testing.T) {
+		if err := tc.SelectByText(single, "Cherry"); err != nil {
+			t.Fatalf("cannot select: %s", err)
+		}
+		check(t, single, "c")
+	})
+	t.Run("ByIndex", func(t *testing.T) {
+		if err := tc.SelectByIndex(single, 0); err != nil {
+			t.Fatalf("cannot select: %s", err)
+		}
+		check(t, single, "a")
+	})
+	t.Run("Multiple", func(t *testing.T) {
+		if err := tc.SelectByValue(multi, "a", "c"); err != nil {
+			t.Fatalf("cannot select: %s", err)
+		}
+		check(t, multi, "a", "c")
+	})
+	t.Run("Duplicated", func(t *testing.T) {
+		if err := tc.SelectByValue(multi, "b", "b"); err != nil {
+			t.Fatalf("cannot select: %s", err)
+		}
+		check(t, multi, "b")
+	})
+	t.Run("Clear", func(t *testing.T) {
+		if err := tc.SelectByValue(multi); err != nil {
+			t.Fatalf("cannot clear: %s", err)
+		}
+		check(t, multi)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		if err := tc.SelectByValue(single, "a", "b"); err == nil {
+			t.Error("expected error when selecting 2 options in single select")
+		}
+		if err := tc.SelectByValue(single, "x"); err == nil {
+			t.Error("expected error when selecting non-existing option")
+		}
+	})
+	t.Run("Events", func(t *testing.T) {
+		if ev := tc.formEvents(t); !strings.Contains(ev, "multi:change") {
+			t.Fatalf("unexpected events: %s", ev)
+		}
+	})
+}
+
+func (tc *cmdrTestCase) testSetChecked(t *testing.T) {
+	chk, _ := tc.FindElement(marionette.ID, "check", nil)
+	radio, _ := tc.FindElement(marionette.ID, "radio2", nil)
+
+	if err := tc.SetChecked(chk, true); err != nil {
+		t.Fatalf("cannot check: %s", err)
+	}
+	if ok, _ := tc.IsElementSelected(chk); !ok {
+		t.Error("checkbox is not checked")
+	}
+
+	if err := tc.SetChecked(radio, true); err != nil {
+		t.Fatalf("cannot check: %s", err)
+	}
+	other, _ := tc.FindElement(marionette.ID, "radio1", nil)
+	if ok, _ := tc.IsElementSelected(other); ok {
+		t.Error("other radio is still checked")
+	}
+
+	ev := tc.formEvents(t)
+	if !strings.Contains(ev, "check:change") || !strings.Contains(ev, "radio2:change") {
+		t.Fatalf("unexpected events: %s", ev)
+	}
+}
+
+func (tc *cmdrTestCase) testSetInputValue(t *testing.T) {
+	find := func(id string) *marionette.WebElement {
+		el, err := tc.FindElement(marionette.ID, id, nil)
+		if err != nil {
+			t.Fatalf("cannot find #%s: %s", id, err)
+		}
+		return el
+	}
+	when := time.Date(2019, 2, 28, 13, 45, 0, 0, time.UTC)
+
+	if err := tc.SetDate(find("date"), when); err != nil {
+		t.Errorf("cannot set date: %s", err)
+	}
+	if err := tc.SetTime(find("time"), when); err != nil {
+		t.Errorf("cannot set time: %s", err)
+	}
+	if err := tc.SetColor(find("color"), "#336699"); err != nil {
+		t.Errorf("cannot set color: %s", err)
+	}
+	v, err := tc.SetRange(find("range"), 5)
+	if err != nil {
+		t.Errorf("cannot set range: %s", err)
+	}
+	if v != 4 && v != 6 {
+		t.Errorf("unexpected range value: %f", v)
+	}
+
+	ev := tc.formEvents(t)
+	for _, id := range []string{"date", "time", "color", "range"} {
+		if !strings.Contains(ev, id+":input") || !strings.Contains(ev, id+":change") {
+			t.Errorf("missing events of %s: %s", id, ev)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import "testing"
+
+func (tc *cmdrTestCase) testInitScript(t *testing.T) {
+	seen := func(t *testing.T) (ret string) {
+		tc.loadTestHTML("initscript.html")(t)
+		if err := tc.ExecuteScript(`return window.seen`, &ret); err != nil {
+			t.Fatalf("cannot get result: %s", err)
+		}
+		return
+	}
+
+	for _, scope := range []InitScriptScope{InitScriptTab, InitScriptBrowser} {
+		script, err := tc.AddInitScript(`window.injected = "yes";`, scope)
+		if err != nil {
+			t.Fatalf("cannot add init script (scope %d): %s", scope, err)
+		}
+		if v := seen(t); v != "yes" {
+			t.Errorf("init script (scope %d) does not run before page: %s", scope, v)
+		}
+
+		if err = tc.RemoveInitScript(script); err != nil {
+			t.Fatalf("cannot remove init script (scope %d): %s", scope, err)
+		}
+		if v := seen(t); v != "undefined" {
+			t.Errorf("init script (scope %d) still runs after removed: %s", scope, v)
+		}
+	}
+
+	// bare assignment lands on global of the sandbox, see AddInitScript
+	script, err := tc.AddInitScript(`injected = "bare";`, InitScriptTab)
+	if err != nil {
+		t.Fatalf("cannot add init script: %s", err)
+	}
+	defer tc.RemoveInitScript(script)
+	if v := seen(t); v != "undefined" {
+		t.Errorf("bare assignment is visible to page: %s", v)
+	}
+}
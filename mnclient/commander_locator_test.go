@@ -0,0 +1,68 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) testLocate(t *testing.T) {
+	cases := []struct {
+		name   string
+		loc    *Locator
+		expect []string
+	}{
+		{"Simple", CSS("form"), []string{"f0", "f1", "f2", "f3", "f4"}},
+		{"Within", CSS("form").Within(CSS("#main")), []string{"f1", "f2", "f3", "f4"}},
+		{"Has", CSS("form").Has(Text("Login")), []string{"f1", "f3", "f4"}},
+		{"Visible", CSS("form").Has(Text("Login")).Visible(), []string{"f1", "f4"}},
+		{"Nth", CSS("form").Has(Text("Login")).Nth(2), []string{"f4"}},
+		{"Last", CSS("form").HasNot(ExactText("Login")).Last(), []string{"f2"}},
+		{"Find", XPath("//form[@id='f2']").Find(CSS("button")), nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			els, err := tc.LocateAll(c.loc)
+			if err != nil {
+				t.Fatalf("cannot locate %s: %s", c.loc, err)
+			}
+			if c.expect == nil {
+				if len(els) != 1 {
+					t.Fatalf("expected 1 element, got %d", len(els))
+				}
+				if txt, _ := els[0].Text(); txt != "Register" {
+					t.Fatalf("unexpected element: %s", txt)
+				}
+				return
+			}
+			if len(els) != len(c.expect) {
+				t.Fatalf("expected %v, got %d elements", c.expect, len(els))
+			}
+			for idx, el := range els {
+				if id, _ := el.Attr("id"); id != c.expect[idx] {
+					t.Fatalf("expected %v, got %s at #%d", c.expect, id, idx)
+				}
+			}
+		})
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := tc.Locate(CSS("form").Has(Text("Logout")))
+		e, ok := err.(*marionette.ErrDriver)
+		if !ok || e.Type != marionette.ErrNoSuchElement {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) queryPermission(name string) (ret string, err error) {
+	ch, err := tc.ExecuteAsyncScript(`
+const [name, done] = arguments;
+navigator.permissions.query({name}).then(s => done(s.state), e => done(String(e)));
+`, name)
+	if err != nil {
+		return
+	}
+	res := <-ch
+	ret, _ = res.Result.(string)
+	return ret, res.Err
+}
+
+func (tc *cmdrTestCase) testSetPermission(t *testing.T) {
+	for _, state := range []string{
+		marionette.PermissionDenied,
+		marionette.PermissionGranted,
+	} {
+		if err := tc.SetPermission("geolocation", state); err != nil {
+			t.Fatalf("cannot set permission to %s: %s", state, err)
+		}
+		actual, err := tc.queryPermission("geolocation")
+		if err != nil {
+			t.Fatalf("cannot query permission: %s", err)
+		}
+		if actual != state {
+			t.Fatalf("expected %s, got %s", state, actual)
+		}
+	}
+}
+
+func (tc *cmdrTestCase) testSetPermissionFor(t *testing.T) {
+	origin := "http://localhost:9487"
+	err := tc.SetPermissionFor(origin, "geolocation", marionette.PermissionDenied)
+	if err != nil {
+		t.Fatalf("cannot set permission: %s", err)
+	}
+	defer tc.ResetPermissionFor(origin, "geolocation")
+
+	tc.loadTestHTML("element.html")(t)
+	actual, err := tc.queryPermission("geolocation")
+	if err != nil {
+		t.Fatalf("cannot query permission: %s", err)
+	}
+	if actual != marionette.PermissionDenied {
+		t.Fatalf("unexpected state: %s", actual)
+	}
+}
+
+func (tc *cmdrTestCase) testSetGeolocation(t *testing.T) {
+	origin := "http://localhost:9487"
+	err := tc.SetPermissionFor(origin, "geolocation", marionette.PermissionGranted)
+	if err != nil {
+		t.Fatalf("cannot set permission: %s", err)
+	}
+	defer tc.ResetPermissionFor(origin, "geolocation")
+
+	if err = tc.SetGeolocation(25.033, 121.565, 10); err != nil {
+		t.Fatalf("cannot set geolocation: %s", err)
+	}
+	defer tc.ClearGeolocation()
+
+	tc.loadTestHTML("element.html")(t)
+	ch, err := tc.ExecuteAsyncScript(`
+const done = arguments[0];
+navigator.geolocation.getCurrentPosition(
+  p => done([p.coords.latitude, p.coords.longitude]),
+  e => done(e.message),
+);
+`)
+	if err != nil {
+		t.Fatalf("cannot exec js: %s", err)
+	}
+	res := <-ch
+	if res.Err != nil {
+		t.Fatalf("js error: %s", res.Err)
+	}
+	pos, ok := res.Result.([]interface{})
+	if !ok || len(pos) != 2 {
+		t.Fatalf("unexpected result: %#v", res.Result)
+	}
+	if pos[0] != 25.033 || pos[1] != 121.565 {
+		t.Fatalf("unexpected position: %v", pos)
+	}
+}
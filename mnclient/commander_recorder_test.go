@@ -0,0 +1,73 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) testRecorder(t *testing.T) {
+	r := NewRecorder(tc.Commander)
+	if err := r.Poll(); err != nil {
+		t.Fatalf("cannot install recorder: %s", err)
+	}
+
+	input, _ := tc.FindElement(marionette.Selector, "[name=q]", nil)
+	pw, _ := tc.FindElement(marionette.Selector, "[name=pw]", nil)
+	lang, _ := tc.FindElement(marionette.ID, "lang", nil)
+	btn, _ := tc.FindElement(marionette.ID, "go", nil)
+	if err := tc.ElementSendKeys(input, "hello"); err != nil {
+		t.Fatalf("cannot type: %s", err)
+	}
+	if err := tc.ElementSendKeys(pw, "secret"); err != nil {
+		t.Fatalf("cannot type password: %s", err)
+	}
+	if err := tc.SelectByValue(lang, "fr"); err != nil {
+		t.Fatalf("cannot select: %s", err)
+	}
+	if err := tc.ElementClick(btn); err != nil {
+		t.Fatalf("cannot click: %s", err)
+	}
+	if err := r.Poll(); err != nil {
+		t.Fatalf("cannot poll: %s", err)
+	}
+
+	steps := r.Steps()
+	if len(steps) < 4 ||
+		steps[0].Type != StepNavigate ||
+		steps[1].Type != StepFill || steps[1].Value != "hello" ||
+		steps[2].Type != StepFill || steps[2].Value != RecordedPassword ||
+		steps[len(steps)-1].Type != StepClick {
+		t.Fatalf("unexpected steps: %+v", steps)
+	}
+
+	// SelectByValue dispatches untrusted events, so record it manually
+	rec := append(steps[:len(steps)-1:len(steps)-1], RecordedStep{
+		Type:     StepSelect,
+		Selector: "#lang",
+		Values:   []string{"fr"},
+	}, steps[len(steps)-1])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := rec.Replay(ctx, tc.Commander); err != nil {
+		t.Fatalf("cannot replay: %s", err)
+	}
+	result, _ := tc.FindElement(marionette.ID, "result", nil)
+	if txt, _ := tc.GetElementText(result); txt != "hello|fr" {
+		t.Fatalf("unexpected result: %s", txt)
+	}
+}
@@ -11,7 +11,12 @@
 
 package mnclient
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
 
 func (tc *cmdrTestCase) testExecuteScript(t *testing.T) {
 	t.Run("set-n-get", func(t *testing.T) {
@@ -43,6 +48,62 @@ func (tc *cmdrTestCase) testExecuteScript(t *testing.T) {
 	})
 }
 
+func (tc *cmdrTestCase) testExecuteScriptElements(t *testing.T) {
+	body, err := tc.FindElement(marionette.TagName, "body", nil)
+	if err != nil {
+		t.Fatalf("cannot find body: %s", err)
+	}
+
+	arg := struct {
+		List []*marionette.WebElement          `json:"list"`
+		Map  map[string]*marionette.WebElement `json:"map"`
+	}{
+		List: []*marionette.WebElement{body},
+		Map:  map[string]*marionette.WebElement{"x": body},
+	}
+	js := `const [a] = arguments;
+return {
+  tag: a.list[0].localName + a.map.x.localName,
+  el: a.map.x,
+  list: [a.list[0]],
+};`
+
+	t.Run("typed", func(t *testing.T) {
+		var ret struct {
+			Tag  string                   `json:"tag"`
+			El   *marionette.WebElement   `json:"el"`
+			List []*marionette.WebElement `json:"list"`
+		}
+		if err := tc.ExecuteScript(js, &ret, arg); err != nil {
+			t.Fatalf("cannot exec js: %s", err)
+		}
+		if ret.Tag != "bodybody" {
+			t.Fatalf("unexpected tag: %s", ret.Tag)
+		}
+		if *ret.El != *body || len(ret.List) != 1 || *ret.List[0] != *body {
+			t.Fatalf("unexpected elements: %+v", ret)
+		}
+	})
+
+	t.Run("untyped", func(t *testing.T) {
+		var ret map[string]interface{}
+		if err := tc.ExecuteScript(js, &ret, arg); err != nil {
+			t.Fatalf("cannot exec js: %s", err)
+		}
+		el, ok := ret["el"].(*marionette.WebElement)
+		if !ok || *el != *body {
+			t.Fatalf("unexpected element: %#v", ret["el"])
+		}
+		list, _ := ret["list"].([]interface{})
+		if len(list) != 1 {
+			t.Fatalf("unexpected list: %#v", ret["list"])
+		}
+		if el, ok := list[0].(*marionette.WebElement); !ok || *el != *body {
+			t.Fatalf("unexpected element in list: %#v", list[0])
+		}
+	})
+}
+
 func (tc *cmdrTestCase) testExecuteScriptIn(t *testing.T) {
 	t.Run("set-n-get", func(t *testing.T) {
 		js := `window.testProp = arguments[0]; return window.testProp`
@@ -184,3 +245,19 @@ func (tc *cmdrTestCase) testExecuteAsyncScriptIn(t *testing.T) {
 		}
 	})
 }
+
+func (tc *cmdrTestCase) testEval(t *testing.T) {
+	n, err := Eval[int](tc.Commander, `return arguments[0] + 1`, 1)
+	if err != nil || n != 2 {
+		t.Fatalf("unexpected result: %d, %v", n, err)
+	}
+
+	js := `const [v, done] = arguments; setTimeout(() => done(v), 10)`
+	vals, err := All(
+		EvalAsync[string](tc.Commander, js, "a"),
+		EvalAsync[string](tc.Commander, js, "b"),
+	).Await(context.Background())
+	if err != nil || len(vals) != 2 || vals[0] != "a" || vals[1] != "b" {
+		t.Fatalf("unexpected result: %v, %v", vals, err)
+	}
+}
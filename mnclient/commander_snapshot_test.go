@@ -0,0 +1,59 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) testSnapshot(t *testing.T) {
+	var els []*marionette.WebElement
+	for _, id := range []string{"hidden", "text", "checked", "disabled"} {
+		el, err := tc.FindElement(marionette.ID, id, nil)
+		if err != nil {
+			t.Fatalf("cannot find #%s: %s", id, err)
+		}
+		els = append(els, el)
+	}
+
+	snaps, err := tc.Snapshot(
+		els,
+		FieldTag, FieldDisplayed, FieldEnabled, FieldSelected, FieldRect,
+		FieldAttr("type"), FieldProp("value"), FieldStyle("display"),
+	)
+	if err != nil {
+		t.Fatalf("cannot take snapshot: %s", err)
+	}
+	if len(snaps) != len(els) {
+		t.Fatalf("expected %d snapshots, got %d", len(els), len(snaps))
+	}
+
+	hidden, text, checked, disabled := snaps[0], snaps[1], snaps[2], snaps[3]
+	if hidden.Tag != "div" || hidden.Displayed || hidden.Styles["display"] != "none" {
+		t.Errorf("unexpected #hidden: %+v", hidden)
+	}
+	if _, ok := hidden.Attrs["type"]; ok {
+		t.Errorf("unexpected attribute of #hidden: %+v", hidden.Attrs)
+	}
+	if text.Tag != "input" || !text.Displayed || text.Props["value"] != "demo" ||
+		text.Attrs["type"] != "text" || text.Rect.W <= 0 {
+		t.Errorf("unexpected #text: %+v", text)
+	}
+	if !checked.Selected || !checked.Enabled {
+		t.Errorf("unexpected #checked: %+v", checked)
+	}
+	if disabled.Enabled || disabled.Element.UUID != els[3].UUID {
+		t.Errorf("unexpected #disabled: %+v", disabled)
+	}
+}
@@ -0,0 +1,67 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"reflect"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) testTable(t *testing.T) {
+	el, err := tc.FindElement(marionette.ID, "data", nil)
+	if err != nil {
+		t.Fatalf("cannot find table: %s", err)
+	}
+
+	t.Run("Rows", func(t *testing.T) {
+		rows, err := tc.TableRows(el)
+		if err != nil {
+			t.Fatalf("cannot extract table: %s", err)
+		}
+		expected := [][]string{
+			{"Name", "Price", "Qty"},
+			{"Apple", "1.5", "10"},
+			{"Apple", "2", "3"},
+			{"Banana", "Banana", "1,200"},
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Fatalf("unexpected result: %+v", rows)
+		}
+	})
+
+	t.Run("Maps", func(t *testing.T) {
+		rows, err := tc.TableMaps(el)
+		if err != nil {
+			t.Fatalf("cannot extract table: %s", err)
+		}
+		if len(rows) != 3 || rows[1]["Qty"] != "3" {
+			t.Fatalf("unexpected result: %+v", rows)
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		type row struct {
+			Name  string  `table:"Name"`
+			Price float64 `table:"#1"`
+			Qty   int     `table:"Qty"`
+		}
+		var rows []row
+		if err := tc.TableUnmarshal(el, &rows); err != nil {
+			t.Fatalf("cannot extract table: %s", err)
+		}
+		if len(rows) != 3 || rows[0].Price != 1.5 || rows[2].Qty != 1200 {
+			t.Fatalf("unexpected result: %+v", rows)
+		}
+	})
+}
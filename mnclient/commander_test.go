@@ -98,6 +98,8 @@ func TestCommander(t *testing.T) {
 		tc.testElementSendKeys,
 		append(prereq, tc.testGetElementProperty)...,
 	))
+	t.Run("ElementObject", tc.with(tc.testElementObject, prereq...))
+	t.Run("Snapshot", tc.with(tc.testSnapshot, prereq...))
 
 	// informational commands
 	t.Run("GetCapabilities", tc.testGetCapabilities)
@@ -112,6 +114,12 @@ func TestCommander(t *testing.T) {
 	t.Run("ExecuteScriptIn", tc.with(tc.testExecuteScriptIn))
 	t.Run("ExecuteAsyncScript", tc.with(tc.testExecuteAsyncScript))
 	t.Run("ExecuteAsyncScriptIn", tc.with(tc.testExecuteAsyncScriptIn))
+	t.Run("Eval", tc.with(tc.testEval))
+	t.Run("InitScript", tc.with(tc.testInitScript))
+	t.Run("CaptureConsole", tc.with(tc.testCaptureConsole))
+	t.Run("ExecuteScriptElements", tc.with(
+		tc.testExecuteScriptElements, tc.loadTestHTML("element.html"),
+	))
 
 	// cookie
 	t.Run("Cookies", tc.with(tc.testCookies, prereq...))
@@ -174,6 +182,14 @@ func TestCommander(t *testing.T) {
 		tc.testGetElementText,
 	}
 	t.Run("PerformActions", tc.with(tc.testPerformAction, prereq...))
+	t.Run("PerformActionsModifier", tc.with(tc.testPerformActionModifier, prereq...))
+	t.Run("PerformActionsElementOrigin", tc.with(tc.testPerformActionElementOrigin, prereq...))
+	t.Run("PerformActionsScroll", tc.with(tc.testPerformActionScroll, prereq...))
+	t.Run("PerformActionsTap", tc.with(tc.testPerformActionTap, prereq...))
+	t.Run("PerformActionsType", tc.with(tc.testPerformActionType, prereq...))
+	t.Run("PerformActionsHumanMove", tc.with(tc.testPerformActionHumanMove, prereq...))
+	t.Run("DragAndDrop", tc.with(tc.testDragAndDrop, tc.loadTestHTML("drag.html")))
+	t.Run("Recorder", tc.with(tc.testRecorder, tc.loadTestHTML("recorder.html")))
 	t.Run("ReleaseActions", tc.testReleaseActions)
 
 	// frames
@@ -199,6 +215,37 @@ func TestCommander(t *testing.T) {
 		tc.testGetChromeHandles,
 	))
 
+	// form
+	prereq = []func(*testing.T){tc.loadTestHTML("form.html")}
+	t.Run("Select", tc.with(tc.testSelect, prereq...))
+	t.Run("SetChecked", tc.with(tc.testSetChecked, prereq...))
+	t.Run("SetInputValue", tc.with(tc.testSetInputValue, prereq...))
+
+	// table
+	prereq = []func(*testing.T){tc.loadTestHTML("table.html")}
+	t.Run("Table", tc.with(tc.testTable, prereq...))
+
+	// locator
+	prereq = []func(*testing.T){tc.loadTestHTML("locator.html")}
+	t.Run("Locate", tc.with(tc.testLocate, prereq...))
+
+	// unmarshal
+	prereq = []func(*testing.T){tc.loadTestHTML("unmarshal.html")}
+	t.Run("Unmarshal", tc.with(tc.testUnmarshal, prereq...))
+
+	// permission
+	prereq = []func(*testing.T){tc.loadTestHTML("element.html")}
+	t.Run("SetPermission", tc.with(tc.testSetPermission, prereq...))
+	t.Run("SetPermissionFor", tc.with(tc.testSetPermissionFor))
+	t.Run("SetGeolocation", tc.with(tc.testSetGeolocation))
+
+	// webauthn
+	prereq = []func(*testing.T){tc.loadTestHTML("element.html")}
+	t.Run("WebAuthn", tc.with(tc.testWebAuthn, prereq...))
+
+	// session, deletes and recreates current session
+	t.Run("Session", tc.testSession)
+
 	// reftest, must run last
 	//
 	// it's not stable so I will comment this test and rewrite when I have
@@ -0,0 +1,54 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"reflect"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) testUnmarshal(t *testing.T) {
+	type item struct {
+		ID    int      `mn:"attr=data-id"`
+		Name  string   `mn:"css=.name"`
+		Price float64  `mn:"css=.price,text"`
+		Link  string   `mn:"css=a,attr=href"`
+		Tags  []string `mn:"css=.tag"`
+	}
+	var page struct {
+		Title string                 `mn:"css=h1"`
+		Items []item                 `mn:"css=.item"`
+		Next  *marionette.WebElement `mn:"xpath=//a[@class='next']"`
+		Prev  *marionette.WebElement `mn:"css=a.prev,optional"`
+		Count int                    `mn:"css=.items,prop=childElementCount"`
+	}
+
+	if err := Unmarshal(tc.Commander, nil, &page); err != nil {
+		t.Fatalf("cannot unmarshal: %s", err)
+	}
+
+	expected := []item{
+		{ID: 1, Name: "Apple", Price: 1.5, Link: "/apple", Tags: []string{"fruit", "red"}},
+		{ID: 2, Name: "Banana", Price: 2, Link: "/banana", Tags: []string{}},
+	}
+	if page.Title != "Products" || page.Count != 2 || !reflect.DeepEqual(page.Items, expected) {
+		t.Fatalf("unexpected result: %+v", page)
+	}
+	if page.Next == nil || page.Prev != nil {
+		t.Fatalf("unexpected elements: %+v, %+v", page.Next, page.Prev)
+	}
+	if txt, _ := tc.GetElementText(page.Next); txt != "next" {
+		t.Fatalf("unexpected text of next link: %s", txt)
+	}
+}
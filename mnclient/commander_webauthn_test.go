@@ -0,0 +1,94 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+func (tc *cmdrTestCase) testWebAuthn(t *testing.T) {
+	id, err := tc.AddVirtualAuthenticator(&marionette.VirtualAuthenticator{
+		Protocol:            marionette.AuthenticatorCTAP2,
+		Transport:           marionette.AuthenticatorUSB,
+		HasResidentKey:      true,
+		HasUserVerification: true,
+		IsUserConsenting:    true,
+	})
+	if err != nil {
+		t.Fatalf("cannot add authenticator: %s", err)
+	}
+	if id == "" {
+		t.Fatal("empty authenticator id")
+	}
+	defer tc.RemoveVirtualAuthenticator(id)
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+	cred := &marionette.Credential{
+		ID:                   []byte("my-credential"),
+		IsResidentCredential: true,
+		RpID:                 "localhost",
+		PrivateKey:           pkcs8,
+		UserHandle:           []byte("user"),
+	}
+
+	ok := t.Run("AddCredential", func(t *testing.T) {
+		if err := tc.AddCredential(id, cred); err != nil {
+			t.Fatalf("cannot add credential: %s", err)
+		}
+	})
+	if !ok {
+		t.Skip("unexpected error in AddCredential(), skip now")
+	}
+
+	t.Run("GetCredentials", func(t *testing.T) {
+		list, err := tc.GetCredentials(id)
+		if err != nil {
+			t.Fatalf("cannot get credentials: %s", err)
+		}
+		if l := len(list); l != 1 {
+			t.Fatalf("expected 1 credential, got %d", l)
+		}
+		if !bytes.Equal(list[0].ID, cred.ID) {
+			t.Fatalf("unexpected credential: %+v", list[0])
+		}
+	})
+
+	t.Run("SetUserVerified", func(t *testing.T) {
+		if err := tc.SetUserVerified(id, true); err != nil {
+			t.Fatalf("cannot set user verified: %s", err)
+		}
+	})
+
+	t.Run("RemoveCredential", func(t *testing.T) {
+		if err := tc.RemoveCredential(id, cred.ID); err != nil {
+			t.Fatalf("cannot remove credential: %s", err)
+		}
+		list, _ := tc.GetCredentials(id)
+		if len(list) != 0 {
+			t.Fatalf("expected no credential, got %+v", list)
+		}
+	})
+
+	t.Run("RemoveAllCredentials", func(t *testing.T) {
+		if err := tc.RemoveAllCredentials(id); err != nil {
+			t.Fatalf("cannot remove all credentials: %s", err)
+		}
+	})
+}
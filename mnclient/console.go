@@ -0,0 +1,386 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sources of ConsoleEntry
+const (
+	// console.log/info/warn/error/debug
+	ConsoleAPI = "console"
+	// uncaught exception
+	ConsoleException = "exception"
+	// unhandled promise rejection
+	ConsoleRejection = "rejection"
+)
+
+// ConsoleEntry is a message captured by ConsoleCapture
+type ConsoleEntry struct {
+	// one of ConsoleAPI, ConsoleException and ConsoleRejection
+	Source string `json:"source"`
+	// log, info, warn, error or debug, always "error" for exceptions and
+	// rejections
+	Level string `json:"level"`
+	// arguments of console API joined by space, objects are encoded as json
+	Message string `json:"message"`
+	// where the message comes from, can be empty if unknown
+	URL    string `json:"url"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Stack  string `json:"stack,omitempty"`
+	// url of the page
+	Page string `json:"page"`
+	// in milliseconds since unix epoch
+	Time float64 `json:"time"`
+}
+
+// IsError reports if the entry is an error, including console.error
+func (e ConsoleEntry) IsError() (ok bool) {
+	return e.Level == "error"
+}
+
+func (e ConsoleEntry) String() (ret string) {
+	ret = "[" + e.Level + "] " + e.Message
+	if e.URL != "" {
+		ret += " (" + e.URL + ":" + strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Column) + ")"
+	}
+	return
+}
+
+// ErrConsole denotes errors are reported in console, see ConsoleCapture.Check
+type ErrConsole struct {
+	Entries []ConsoleEntry
+}
+
+func (e *ErrConsole) Error() (ret string) {
+	return "mnclient: " + strconv.Itoa(len(e.Entries)) +
+		" errors in console, first one: " + e.Entries[0].String()
+}
+
+// ErrCaptureStopped denotes the ConsoleCapture is stopped, or the browser is
+// restarted
+var ErrCaptureStopped = errors.New("mnclient: console capture is stopped")
+
+// frame script hooking console and error events, placeholder is replaced with
+// json
+const jsConsoleFrameScript = `(() => {
+const Cu = Components.utils;
+const [msgName] = %ARGS%;
+let active = true;
+
+const fmt = v => {
+  try {
+    v = Cu.waiveXrays(v);
+    if (typeof v === "string") return v;
+    if (v && typeof v === "object" && "message" in v && "stack" in v) {
+      return String(v.name || "Error") + ": " + String(v.message);
+    }
+    const s = JSON.stringify(v);
+    return s === undefined ? String(v) : s;
+  } catch (e) {
+    return String(v);
+  }
+};
+
+// location of first frame in stack
+const frame = stack => {
+  stack = String(stack || "");
+  for (const line of stack.split("\n")) {
+    const m = /@(.*):(\d+):(\d+)$/.exec(line);
+    if (m) return {url: m[1], line: +m[2], column: +m[3], stack};
+  }
+  return {stack};
+};
+
+const hook = win => {
+  if (!win) return;
+  const raw = Cu.waiveXrays(win);
+  const send = entry => {
+    if (!active) return;
+    entry.page = String(win.location.href);
+    entry.time = Date.now();
+    sendAsyncMessage(msgName, entry);
+  };
+
+  for (const level of ["log", "info", "warn", "error", "debug"]) {
+    const orig = raw.console[level];
+    raw.console[level] = Cu.exportFunction(function (...args) {
+      try {
+        // chrome frames are invisible to content, so stack starts at caller
+        const loc = frame(new raw.Error().stack);
+        send(Object.assign(loc, {
+          source: "console",
+          level,
+          message: args.map(fmt).join(" "),
+        }));
+      } catch (e) {}
+      return orig.apply(this, args);
+    }, raw);
+  }
+
+  win.addEventListener("error", e => {
+    if (e.target !== win) return;
+    const err = Cu.waiveXrays(e).error;
+    send({
+      source: "exception",
+      level: "error",
+      message: String(e.message),
+      url: String(e.filename),
+      line: e.lineno,
+      column: e.colno,
+      stack: err && err.stack ? String(err.stack) : "",
+    });
+  }, true);
+
+  win.addEventListener("unhandledrejection", e => {
+    const reason = Cu.waiveXrays(e).reason;
+    const loc = frame(reason && reason.stack);
+    send(Object.assign(loc, {
+      source: "rejection",
+      level: "error",
+      message: fmt(reason),
+    }));
+  }, true);
+};
+
+const onCreated = e => hook(e.target.defaultView);
+const onStop = () => {
+  active = false;
+  removeEventListener("DOMWindowCreated", onCreated, true);
+  removeMessageListener(msgName + ":stop", onStop);
+};
+addEventListener("DOMWindowCreated", onCreated, true);
+addMessageListener(msgName + ":stop", onStop);
+hook(content);
+})();
+`
+
+const jsStartConsole = jsFindBrowser + `
+const [url, id, handle, max] = arguments;
+const mm = handle ? (findBrowser(handle) || {}).messageManager : Services.mm;
+if (!mm) return false;
+
+const name = "mnclient:console:" + id;
+const buf = [];
+let dropped = 0;
+const onMessage = msg => {
+  buf.push(msg.data);
+  if (buf.length > max) {
+    buf.shift();
+    dropped++;
+  }
+};
+const drain = holder => {
+  holder.json = JSON.stringify({entries: buf.splice(0), dropped});
+  dropped = 0;
+};
+
+const drainTopic = "mnclient-console-drain-" + id;
+const stopTopic = "mnclient-console-stop-" + id;
+const observer = {
+  observe(subject, topic) {
+    drain(subject.wrappedJSObject);
+    if (topic !== stopTopic) return;
+
+    mm.removeMessageListener(name, onMessage);
+    mm.removeDelayedFrameScript(url);
+    if (mm.broadcastAsyncMessage) {
+      mm.broadcastAsyncMessage(name + ":stop", {});
+    } else {
+      mm.sendAsyncMessage(name + ":stop", {});
+    }
+    Services.obs.removeObserver(observer, drainTopic);
+    Services.obs.removeObserver(observer, stopTopic);
+  },
+};
+Services.obs.addObserver(observer, drainTopic);
+Services.obs.addObserver(observer, stopTopic);
+
+mm.addMessageListener(name, onMessage);
+mm.loadFrameScript(url, true);
+return true;
+`
+
+const jsNotifyConsole = `
+const holder = {json: null};
+Services.obs.notifyObservers({wrappedJSObject: holder}, arguments[0]);
+return holder.json;
+`
+
+// default buffer size of CaptureConsole
+const defaultConsoleMax = 1000
+
+// ConsoleCapture collects console messages, uncaught exceptions and unhandled
+// promise rejections
+//
+// Captured entries are buffered in chrome context, so entries are kept across
+// navigations. Call Drain between steps of your test to read them, or register
+// handlers with OnEntry to receive them as a stream.
+//
+// Drain, Check and Stop switch context, so they should be called in the
+// goroutine sending other commands, see "Chrome context" in package document.
+type ConsoleCapture struct {
+	cl  *Commander
+	id  string
+	tab string
+
+	lock     sync.Mutex
+	dropped  int
+	handlers []func(ConsoleEntry)
+}
+
+// CaptureConsole starts capturing console messages of current tab or all tabs
+//
+// It hooks console API and error events with frame scripts before page scripts
+// run, see AddInitScript for limitations. Current page is also hooked, but
+// messages logged before are not captured.
+//
+// At most max entries are buffered, older entries are dropped when exceeded.
+// max <= 0 means 1000.
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (s *Commander) CaptureConsole(scope InitScriptScope, max int) (ret *ConsoleCapture, err error) {
+	if max <= 0 {
+		max = defaultConsoleMax
+	}
+	id, err := newScriptID()
+	if err != nil {
+		return
+	}
+	c := &ConsoleCapture{cl: s, id: id}
+
+	if scope == InitScriptTab {
+		if c.tab, err = s.GetWindowHandle(); err != nil {
+			return
+		}
+	}
+
+	args, err := json.Marshal([]string{"mnclient:console:" + c.id})
+	if err != nil {
+		return
+	}
+	src := "data:application/javascript," + url.PathEscape(
+		strings.Replace(jsConsoleFrameScript, "%ARGS%", string(args), 1),
+	)
+
+	var found bool
+	err = s.runInChrome(func() error {
+		return s.ExecuteScript(jsStartConsole, &found, src, c.id, c.tab, max)
+	})
+	if err == nil && !found {
+		err = ErrTabNotFound
+	}
+	if err != nil {
+		return
+	}
+	return c, nil
+}
+
+func (c *ConsoleCapture) notify(topic string) (ret []ConsoleEntry, err error) {
+	var data *string
+	err = c.cl.runInChrome(func() error {
+		return c.cl.ExecuteScript(jsNotifyConsole, &data, topic+c.id)
+	})
+	if err != nil {
+		return
+	}
+	if data == nil {
+		return nil, ErrCaptureStopped
+	}
+
+	var res struct {
+		Entries []ConsoleEntry `json:"entries"`
+		Dropped int            `json:"dropped"`
+	}
+	if err = json.Unmarshal([]byte(*data), &res); err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	c.dropped += res.Dropped
+	handlers := c.handlers
+	c.lock.Unlock()
+	for _, f := range handlers {
+		for _, e := range res.Entries {
+			f(e)
+		}
+	}
+	return res.Entries, nil
+}
+
+// OnEntry registers f, which is called with every entry read by Drain, Check or
+// Stop, in the goroutine calling them
+//
+//	capture.OnEntry(func(e mnclient.ConsoleEntry) { t.Log(e) })
+//	// ... drive the page
+//	capture.Drain()
+func (c *ConsoleCapture) OnEntry(f func(ConsoleEntry)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.handlers = append(c.handlers, f)
+}
+
+// Drain returns and removes buffered entries
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (c *ConsoleCapture) Drain() (ret []ConsoleEntry, err error) {
+	return c.notify("mnclient-console-drain-")
+}
+
+// Dropped returns number of entries dropped due to full buffer so far
+func (c *ConsoleCapture) Dropped() (ret int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.dropped
+}
+
+// Stop stops capturing, and returns entries not drained yet
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (c *ConsoleCapture) Stop() (ret []ConsoleEntry, err error) {
+	return c.notify("mnclient-console-stop-")
+}
+
+// Check drains the buffer, and returns *ErrConsole if there are errors
+//
+// Useful to fail a test on any javascript error:
+//
+//	if err := capture.Check(); err != nil {
+//		t.Fatal(err)
+//	}
+func (c *ConsoleCapture) Check() (err error) {
+	entries, err := c.Drain()
+	if err != nil {
+		return
+	}
+
+	var errs []ConsoleEntry
+	for _, e := range entries {
+		if e.IsError() {
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) > 0 {
+		err = &ErrConsole{Entries: errs}
+	}
+	return
+}
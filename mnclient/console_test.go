@@ -0,0 +1,106 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"strings"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// fakeConsole responds console scripts with preset buffer
+type fakeConsole struct {
+	fakeChrome
+	// json returned by drain, nil means stopped
+	buf    interface{}
+	topics []string
+}
+
+func (f *fakeConsole) handle(cmd mncmd.Command) (interface{}, error) {
+	if c, ok := cmd.(*mncmd.ExecuteScript); ok && c.Script == jsNotifyConsole {
+		if f.ctx != marionette.ChromeContext {
+			return nil, &marionette.ErrDriver{Type: marionette.ErrJavascriptError}
+		}
+		f.topics = append(f.topics, c.Args[0].(string))
+		return fakeValue(f.buf), nil
+	}
+	return f.fakeChrome.handle(cmd)
+}
+
+func TestConsoleCapture(t *testing.T) {
+	f := &fakeConsole{fakeChrome: fakeChrome{ctx: marionette.ContentContext, found: true}}
+	cl := &Commander{Sender: &fakeSender{handler: f.handle}}
+
+	c, err := cl.CaptureConsole(InitScriptTab, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if args := f.args[0]; args[1] != c.id || args[2] != "42" || args[3] != defaultConsoleMax {
+		t.Fatalf("unexpected args: %v", args)
+	}
+
+	f.buf = `{"entries":[` +
+		`{"source":"console","level":"log","message":"hi","url":"http://a/x.js","line":3,"column":5},` +
+		`{"source":"exception","level":"error","message":"Error: boom","url":"http://a/x.js","line":7,"column":1}` +
+		`],"dropped":2}`
+	var streamed []string
+	c.OnEntry(func(e ConsoleEntry) { streamed = append(streamed, e.Message) })
+	entries, err := c.Drain()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(entries) != 2 || entries[0].Message != "hi" || entries[1].IsError() != true {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[1].String() != "[error] Error: boom (http://a/x.js:7:1)" {
+		t.Fatalf("unexpected string: %s", entries[1])
+	}
+	if strings.Join(streamed, ",") != "hi,Error: boom" {
+		t.Fatalf("unexpected streamed entries: %v", streamed)
+	}
+	if c.Dropped() != 2 {
+		t.Fatalf("unexpected dropped: %d", c.Dropped())
+	}
+
+	err = c.Check()
+	e, ok := err.(*ErrConsole)
+	if !ok || len(e.Entries) != 1 || e.Entries[0].Source != ConsoleException {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(e.Error(), "Error: boom") {
+		t.Fatalf("unexpected message: %s", e)
+	}
+
+	f.buf = `{"entries":[],"dropped":0}`
+	if _, err = c.Stop(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f.buf = nil
+	if _, err = c.Drain(); err != ErrCaptureStopped {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expect := []string{
+		"mnclient-console-drain-" + c.id,
+		"mnclient-console-drain-" + c.id,
+		"mnclient-console-stop-" + c.id,
+		"mnclient-console-drain-" + c.id,
+	}
+	if strings.Join(f.topics, ",") != strings.Join(expect, ",") {
+		t.Fatalf("unexpected topics: %v", f.topics)
+	}
+	if f.ctx != marionette.ContentContext {
+		t.Fatalf("context is not restored: %s", f.ctx)
+	}
+}
@@ -10,4 +10,12 @@
 // See License.txt for further information.
 
 // Package mnclient contains few "easier to use" clients
+//
+// Chrome context
+//
+// Some helpers need privileged code, like SetPermissionFor, SetGeolocation,
+// AddInitScript and CaptureConsole. They switch to chrome context, execute
+// scripts and switch back to previous context before returning. As context is a
+// state of the session, they MUST NOT be used with ContextManager, or
+// concurrently with other commands depending on current context.
 package mnclient
@@ -0,0 +1,194 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"errors"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// DragMode denotes how DragAndDrop works
+type DragMode int
+
+const (
+	// use pointer actions, and synthesize HTML5 drag events if src is draggable
+	// but dst does not receive the drop
+	DragAuto DragMode = iota
+	// use pointer actions only
+	DragPointer
+	// synthesize HTML5 drag events only
+	DragHTML5
+)
+
+// DropFile is a file dropped by DragAndDrop
+type DropFile struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"` // MIME type
+	Content []byte `json:"data"`
+}
+
+// DragOptions represents options of DragAndDrop
+type DragOptions struct {
+	Mode DragMode
+	// drop position relative to center of dst
+	OffsetX int
+	OffsetY int
+	// duration of pointer movement in milliseconds
+	Duration int
+	// files to drop, always synthesized as HTML5 drop
+	Files []DropFile
+	// extra data set in DataTransfer of synthesized events, like "text/plain"
+	Data map[string]string
+}
+
+// ErrDropRejected denotes the drop target does not accept synthesized drop
+//
+// Drop targets must cancel the "dragover" event to accept drops.
+var ErrDropRejected = errors.New("mnclient: drop target does not accept the drop")
+
+// sandbox keeping state of drop watchers
+const dragSandbox = "mnclient-drag"
+
+const jsDragWatch = `
+const [src, dst] = arguments;
+globalThis.mnDrops = globalThis.mnDrops || new WeakMap();
+const state = {dropped: false};
+globalThis.mnDrops.set(dst, state);
+dst.addEventListener("drop", () => state.dropped = true, {capture: true, once: true});
+return src.draggable;
+`
+
+const jsDragCheck = `
+const [dst] = arguments;
+const state = (globalThis.mnDrops || new WeakMap()).get(dst);
+return !!(state && state.dropped);
+`
+
+const jsDragSynth = `
+const [src, dst, ox, oy, files, data] = arguments;
+const dt = new DataTransfer();
+for (const f of files || []) {
+  const bin = Uint8Array.from(atob(f.data || ""), c => c.charCodeAt(0));
+  dt.items.add(new File([bin], f.name, {type: f.type || ""}));
+}
+for (const [k, v] of Object.entries(data || {})) dt.setData(k, v);
+dst.scrollIntoView({block: "center", inline: "center"});
+
+const rect = el => el.getBoundingClientRect();
+const center = el => [rect(el).left + rect(el).width / 2, rect(el).top + rect(el).height / 2];
+const fire = (el, type, [x, y]) => el.dispatchEvent(new DragEvent(type, {
+  bubbles: true, cancelable: true, composed: true,
+  clientX: x, clientY: y, dataTransfer: dt,
+}));
+
+const [cx, cy] = center(dst);
+const pos = [cx + ox, cy + oy];
+let target = document.elementFromPoint(pos[0], pos[1]);
+if (!target || !dst.contains(target)) target = dst;
+
+if (src) fire(src, "dragstart", center(src));
+fire(target, "dragenter", pos);
+const accepted = !fire(target, "dragover", pos);
+if (accepted) {
+  fire(target, "drop", pos);
+} else {
+  fire(target, "dragleave", pos);
+}
+if (src) fire(src, "dragend", pos);
+return accepted;
+`
+
+// DragAndDrop drags src and drops it onto dst
+//
+// In DragAuto mode, it performs pointer actions first. If src is HTML5
+// draggable but dst does not receive the drop event, which is common as
+// synthesized mouse events seldom start native drag sessions, it synthesizes
+// dragstart/dragenter/dragover/drop events with a DataTransfer.
+//
+// To drop files onto a drop zone, pass nil src with opts.Files. File drops are
+// always synthesized. Passing nil opts uses default options.
+func (s *Commander) DragAndDrop(src, dst *marionette.WebElement, opts *DragOptions) (err error) {
+	if opts == nil {
+		opts = &DragOptions{}
+	}
+	if dst == nil {
+		return errors.New("mnclient: drop target is required")
+	}
+
+	mode := opts.Mode
+	if src == nil || len(opts.Files) > 0 {
+		mode = DragHTML5
+	}
+
+	if mode != DragHTML5 {
+		var draggable bool
+		if mode == DragAuto {
+			err = s.ExecuteScriptIn(
+				dragSandbox, jsDragWatch, &draggable, src, dst,
+			)
+			if err != nil {
+				return
+			}
+		}
+
+		if err = s.dragByPointer(src, dst, opts); err != nil || !draggable {
+			return
+		}
+
+		var dropped bool
+		err = s.ExecuteScriptIn(dragSandbox, jsDragCheck, &dropped, dst)
+		if err != nil || dropped {
+			return
+		}
+	}
+
+	return s.dragBySynth(src, dst, opts)
+}
+
+func (s *Commander) dragByPointer(src, dst *marionette.WebElement, opts *DragOptions) (err error) {
+	var chain marionette.ActionChain
+	mouse := chain.Pointer(marionette.DefaultMouseSource, marionette.PointerMouse)
+	mouse.MoveToElement(src, 0, 0, 0).
+		Down(marionette.MouseLeft).
+		// move a little to start dragging
+		MoveBy(5, 5, 50).
+		MoveToElement(dst, opts.OffsetX, opts.OffsetY, opts.Duration).
+		Up(marionette.MouseLeft)
+
+	if err = s.PerformActions(chain); err != nil {
+		return
+	}
+	return s.ReleaseActions()
+}
+
+func (s *Commander) dragBySynth(src, dst *marionette.WebElement, opts *DragOptions) (err error) {
+	var accepted bool
+	err = s.ExecuteScript(
+		jsDragSynth, &accepted,
+		src, dst, opts.OffsetX, opts.OffsetY, opts.Files, opts.Data,
+	)
+	if err == nil && !accepted {
+		err = ErrDropRejected
+	}
+	return
+}
+
+// DropFiles is identical to DragAndDrop(nil, dst, &DragOptions{Files: files})
+func (s *Commander) DropFiles(dst *marionette.WebElement, files ...DropFile) (err error) {
+	return s.DragAndDrop(nil, dst, &DragOptions{Files: files})
+}
+
+// DragTo drags this element and drops it onto dst, see Commander.DragAndDrop
+func (e *Element) DragTo(dst *marionette.WebElement, opts *DragOptions) (err error) {
+	return e.Commander.DragAndDrop(e.WebElement, dst, opts)
+}
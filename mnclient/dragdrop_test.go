@@ -0,0 +1,119 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"reflect"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// fakeDragPage responds drag scripts with preset results
+type fakeDragPage struct {
+	draggable bool
+	dropped   bool
+	accepted  bool
+	scripts   []string
+}
+
+func (p *fakeDragPage) handle(cmd mncmd.Command) (interface{}, error) {
+	switch c := cmd.(type) {
+	case *mncmd.ExecuteScript:
+		switch c.Script {
+		case jsDragWatch:
+			p.scripts = append(p.scripts, "watch")
+			return fakeValue(p.draggable), nil
+		case jsDragCheck:
+			p.scripts = append(p.scripts, "check")
+			return fakeValue(p.dropped), nil
+		case jsDragSynth:
+			p.scripts = append(p.scripts, "synth")
+			return fakeValue(p.accepted), nil
+		}
+	case *mncmd.PerformActions:
+		p.scripts = append(p.scripts, "actions")
+		return nil, nil
+	case *mncmd.ReleaseActions:
+		return nil, nil
+	}
+	return nil, &marionette.ErrDriver{Type: marionette.ErrUnknownCommand}
+}
+
+func TestDragAndDrop(t *testing.T) {
+	src := &marionette.WebElement{Type: marionette.ElementType, UUID: "src"}
+	dst := &marionette.WebElement{Type: marionette.ElementType, UUID: "dst"}
+
+	cases := []struct {
+		name   string
+		page   fakeDragPage
+		src    *marionette.WebElement
+		opts   *DragOptions
+		steps  []string
+		hasErr bool
+	}{
+		{
+			name:  "NotDraggable",
+			page:  fakeDragPage{},
+			src:   src,
+			steps: []string{"watch", "actions"},
+		},
+		{
+			name:  "PointerWorks",
+			page:  fakeDragPage{draggable: true, dropped: true},
+			src:   src,
+			steps: []string{"watch", "actions", "check"},
+		},
+		{
+			name:  "Fallback",
+			page:  fakeDragPage{draggable: true, accepted: true},
+			src:   src,
+			steps: []string{"watch", "actions", "check", "synth"},
+		},
+		{
+			name:   "Rejected",
+			page:   fakeDragPage{draggable: true},
+			src:    src,
+			opts:   &DragOptions{Mode: DragHTML5},
+			steps:  []string{"synth"},
+			hasErr: true,
+		},
+		{
+			name:  "PointerOnly",
+			page:  fakeDragPage{draggable: true},
+			src:   src,
+			opts:  &DragOptions{Mode: DragPointer},
+			steps: []string{"actions"},
+		},
+		{
+			name:  "Files",
+			page:  fakeDragPage{accepted: true},
+			opts:  &DragOptions{Files: []DropFile{{Name: "a.txt"}}},
+			steps: []string{"synth"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			page := c.page
+			cl := &Commander{Sender: &fakeSender{handler: page.handle}}
+			err := cl.DragAndDrop(c.src, dst, c.opts)
+			if (err != nil) != c.hasErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(page.scripts, c.steps) {
+				t.Fatalf("unexpected steps: %v", page.scripts)
+			}
+		})
+	}
+}
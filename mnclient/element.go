@@ -0,0 +1,171 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// Element is a WebElement bound to the Commander which found it
+//
+// It wraps element related commands into methods, so you can write
+//
+//     el, _ := cl.Find(marionette.Selector, "form")
+//     btn, _ := el.Find(marionette.Selector, "button")
+//     btn.Click()
+//
+// instead of passing WebElement to Commander again and again.
+type Element struct {
+	*marionette.WebElement
+	Commander *Commander
+}
+
+// NewElement binds el to cl
+func NewElement(cl *Commander, el *marionette.WebElement) (ret *Element) {
+	if el == nil {
+		return nil
+	}
+	return &Element{
+		WebElement: el,
+		Commander:  cl,
+	}
+}
+
+func newElements(cl *Commander, els []*marionette.WebElement) (ret []*Element) {
+	ret = make([]*Element, 0, len(els))
+	for _, el := range els {
+		ret = append(ret, NewElement(cl, el))
+	}
+	return
+}
+
+// Find is identical to FindElement(by, qstr, nil), but returns Element
+func (s *Commander) Find(by marionette.FindStrategy, qstr string) (ret *Element, err error) {
+	el, err := s.FindElement(by, qstr, nil)
+	if err == nil {
+		ret = NewElement(s, el)
+	}
+	return
+}
+
+// FindAll is identical to FindElements(by, qstr, nil), but returns Elements
+func (s *Commander) FindAll(by marionette.FindStrategy, qstr string) (ret []*Element, err error) {
+	els, err := s.FindElements(by, qstr, nil)
+	if err == nil {
+		ret = newElements(s, els)
+	}
+	return
+}
+
+// ActiveElement is identical to GetActiveElement, but returns Element
+func (s *Commander) ActiveElement() (ret *Element, err error) {
+	el, err := s.GetActiveElement()
+	if err == nil {
+		ret = NewElement(s, el)
+	}
+	return
+}
+
+// Find finds an element inside this element
+func (e *Element) Find(by marionette.FindStrategy, qstr string) (ret *Element, err error) {
+	el, err := e.Commander.FindElement(by, qstr, e.WebElement)
+	if err == nil {
+		ret = NewElement(e.Commander, el)
+	}
+	return
+}
+
+// FindAll finds all matching elements inside this element
+func (e *Element) FindAll(by marionette.FindStrategy, qstr string) (ret []*Element, err error) {
+	els, err := e.Commander.FindElements(by, qstr, e.WebElement)
+	if err == nil {
+		ret = newElements(e.Commander, els)
+	}
+	return
+}
+
+// Parent retrieves parent element
+func (e *Element) Parent() (ret *Element, err error) {
+	return e.Find(marionette.XPath, "..")
+}
+
+// Click clicks the element
+func (e *Element) Click() (err error) {
+	return e.Commander.ElementClick(e.WebElement)
+}
+
+// Clear clears the text of the element
+func (e *Element) Clear() (err error) {
+	return e.Commander.ElementClear(e.WebElement)
+}
+
+// SendKeys sends keystrokes to the element
+func (e *Element) SendKeys(text string) (err error) {
+	return e.Commander.ElementSendKeys(e.WebElement, text)
+}
+
+// Text retrieves text of the element
+func (e *Element) Text() (ret string, err error) {
+	return e.Commander.GetElementText(e.WebElement)
+}
+
+// TagName retrieves tag name of the element (like "div")
+func (e *Element) TagName() (ret string, err error) {
+	return e.Commander.GetElementTagName(e.WebElement)
+}
+
+// Attr retrieves specified attribute of the element
+func (e *Element) Attr(key string) (ret string, err error) {
+	return e.Commander.GetElementAttribute(e.WebElement, key)
+}
+
+// Prop retrieves specified property of the element
+func (e *Element) Prop(key string) (ret interface{}, err error) {
+	return e.Commander.GetElementProperty(e.WebElement, key)
+}
+
+// CSS retrieves specified css value of the element
+//
+// See Commander.GetElementCSSValue for details.
+func (e *Element) CSS(key string) (ret string, err error) {
+	return e.Commander.GetElementCSSValue(e.WebElement, key)
+}
+
+// Rect retrieves the bounding rect of the element
+func (e *Element) Rect() (ret marionette.Rect, err error) {
+	return e.Commander.GetElementRect(e.WebElement)
+}
+
+// IsDisplayed checks if the element is displayed
+func (e *Element) IsDisplayed() (ret bool, err error) {
+	return e.Commander.IsElementDisplayed(e.WebElement)
+}
+
+// IsEnabled checks if the element is enabled
+func (e *Element) IsEnabled() (ret bool, err error) {
+	return e.Commander.IsElementEnabled(e.WebElement)
+}
+
+// IsSelected checks if the element is selected
+func (e *Element) IsSelected() (ret bool, err error) {
+	return e.Commander.IsElementSelected(e.WebElement)
+}
+
+// Screenshot takes a screenshot of the element in base64-encoded png
+func (e *Element) Screenshot() (img string, err error) {
+	return e.Commander.ScreenshotElement(e.WebElement)
+}
+
+// ScreenshotBytes takes a screenshot of the element in png
+func (e *Element) ScreenshotBytes() (img []byte, err error) {
+	return e.Commander.ScreenshotElementBytes(e.WebElement)
+}
@@ -0,0 +1,73 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"sync"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// fakeSender mocks mnsender.Sender, responses are generated by handler
+type fakeSender struct {
+	handler func(cmd mncmd.Command) (data interface{}, err error)
+
+	lock sync.Mutex
+	sent []mncmd.Command
+}
+
+func (s *fakeSender) Start() (err error) { return }
+func (s *fakeSender) Close()             {}
+func (s *fakeSender) Wait()              {}
+
+func (s *fakeSender) Sync(cmd mncmd.Command) (msg *marionette.Message, err error) {
+	s.lock.Lock()
+	s.sent = append(s.sent, cmd)
+	s.lock.Unlock()
+
+	data, err := s.handler(cmd)
+	msg = &marionette.Message{
+		Type:  1,
+		Data:  data,
+		Error: err,
+	}
+	return
+}
+
+func (s *fakeSender) Async(cmd mncmd.Command) (ch chan *marionette.Message, err error) {
+	msg, _ := s.Sync(cmd)
+	ch = make(chan *marionette.Message, 1)
+	ch <- msg
+	close(ch)
+	return
+}
+
+// commands sent, in order
+func (s *fakeSender) commands() (ret []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, c := range s.sent {
+		ret = append(ret, c.Command())
+	}
+	return
+}
+
+func fakeElem(uuid string) (ret map[string]interface{}) {
+	return map[string]interface{}{
+		"value": map[string]string{marionette.ElementType: uuid},
+	}
+}
+
+func fakeValue(v interface{}) (ret map[string]interface{}) {
+	return map[string]interface{}{"value": v}
+}
@@ -0,0 +1,200 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// SelectedOption represents an option of <select> element
+type SelectedOption struct {
+	Index int    `json:"index"`
+	Value string `json:"value"`
+	Text  string `json:"text"`
+}
+
+const jsSelect = `
+const [el, by, wanted] = arguments;
+if (el.tagName !== "SELECT") {
+  return "not a select element";
+}
+const opts = Array.from(el.options);
+const match = opts.map((o, i) => {
+  switch (by) {
+    case "value": return wanted.includes(o.value);
+    case "text": return wanted.includes(o.text.trim());
+    case "index": return wanted.includes(i);
+  }
+});
+const cnt = match.filter(x => x).length;
+if (cnt !== wanted.length) {
+  return "found " + cnt + " options, expected " + wanted.length;
+}
+if (!el.multiple && cnt !== 1) {
+  return "single select needs exactly one option, got " + cnt;
+}
+opts.forEach((o, i) => o.selected = match[i]);
+el.dispatchEvent(new Event("input", {bubbles: true}));
+el.dispatchEvent(new Event("change", {bubbles: true}));
+return "";
+`
+
+// uniq removes duplicated items, it never returns nil so it is encoded as []
+func uniq[T comparable](items []T) (ret []T) {
+	ret = make([]T, 0, len(items))
+	seen := make(map[T]bool, len(items))
+	for _, i := range items {
+		if !seen[i] {
+			seen[i] = true
+			ret = append(ret, i)
+		}
+	}
+	return
+}
+
+func (s *Commander) selectBy(el *marionette.WebElement, by string, wanted interface{}) (err error) {
+	var msg string
+	if err = s.ExecuteScript(jsSelect, &msg, el, by, wanted); err != nil {
+		return
+	}
+	if msg != "" {
+		err = errors.New("mnclient: " + msg)
+	}
+	return
+}
+
+// SelectByValue selects options of <select> element by their values
+//
+// The selection is set to exactly matching options, and input/change events are
+// dispatched. It returns an error if some values are not found, or not selecting
+// exactly one option in single select. Duplicated values are ignored, and
+// passing no value clears a multiple select.
+func (s *Commander) SelectByValue(el *marionette.WebElement, values ...string) (err error) {
+	return s.selectBy(el, "value", uniq(values))
+}
+
+// SelectByText selects options of <select> element by their (trimmed) text
+//
+// See SelectByValue for details.
+func (s *Commander) SelectByText(el *marionette.WebElement, texts ...string) (err error) {
+	return s.selectBy(el, "text", uniq(texts))
+}
+
+// SelectByIndex selects options of <select> element by their indexes
+//
+// See SelectByValue for details.
+func (s *Commander) SelectByIndex(el *marionette.WebElement, idx ...int) (err error) {
+	return s.selectBy(el, "index", uniq(idx))
+}
+
+// GetSelected retrieves selected options of <select> element
+func (s *Commander) GetSelected(el *marionette.WebElement) (ret []SelectedOption, err error) {
+	err = s.ExecuteScript(`
+const el = arguments[0];
+return Array.from(el.options).
+  map((o, i) => ({index: i, value: o.value, text: o.text.trim(), selected: o.selected})).
+  filter(o => o.selected);
+`, &ret, el)
+	return
+}
+
+// SetChecked checks or unchecks a checkbox or radio button
+//
+// Nothing happens if it is in desired state already, or input/change events are
+// dispatched.
+func (s *Commander) SetChecked(el *marionette.WebElement, checked bool) (err error) {
+	var msg string
+	err = s.ExecuteScript(`
+const [el, checked] = arguments;
+if (el.type !== "checkbox" && el.type !== "radio") {
+  return "not a checkbox or radio button";
+}
+if (el.checked === checked) {
+  return "";
+}
+el.checked = checked;
+el.dispatchEvent(new Event("input", {bubbles: true}));
+el.dispatchEvent(new Event("change", {bubbles: true}));
+return "";
+`, &msg, el, checked)
+	if err == nil && msg != "" {
+		err = errors.New("mnclient: " + msg)
+	}
+	return
+}
+
+// SetInputValue sets value of an <input> element and dispatch input/change events
+//
+// It uses native value setter, so it works with frameworks hooking value property.
+// The browser may sanitize the value (like clamping range input), it returns the
+// value actually set.
+//
+// It is designed for inputs which are hard to type in, like date, time, color and
+// range. Use ElementSendKeys for text inputs.
+func (s *Commander) SetInputValue(el *marionette.WebElement, value string) (actual string, err error) {
+	err = s.ExecuteScript(`
+const [el, value] = arguments;
+const setter = Object.getOwnPropertyDescriptor(HTMLInputElement.prototype, "value").set;
+setter.call(el, value);
+el.dispatchEvent(new Event("input", {bubbles: true}));
+el.dispatchEvent(new Event("change", {bubbles: true}));
+return el.value;
+`, &actual, el, value)
+	return
+}
+
+func (s *Commander) setExact(el *marionette.WebElement, value string) (err error) {
+	actual, err := s.SetInputValue(el, value)
+	if err == nil && actual != value {
+		err = errors.New("mnclient: value " + value + " is rejected by browser")
+	}
+	return
+}
+
+// SetDate sets the value of <input type="date">
+func (s *Commander) SetDate(el *marionette.WebElement, t time.Time) (err error) {
+	return s.setExact(el, t.Format("2006-01-02"))
+}
+
+// SetTime sets the value of <input type="time">, in minute precision
+func (s *Commander) SetTime(el *marionette.WebElement, t time.Time) (err error) {
+	return s.setExact(el, t.Format("15:04"))
+}
+
+// SetDateTime sets the value of <input type="datetime-local">, in minute precision
+func (s *Commander) SetDateTime(el *marionette.WebElement, t time.Time) (err error) {
+	return s.setExact(el, t.Format("2006-01-02T15:04"))
+}
+
+// SetColor sets the value of <input type="color">
+//
+// Color is in "#rrggbb" form, in lower case.
+func (s *Commander) SetColor(el *marionette.WebElement, color string) (err error) {
+	return s.setExact(el, color)
+}
+
+// SetRange sets the value of <input type="range">
+//
+// Browser clamps the value with min, max and step, and the actual value is
+// returned.
+func (s *Commander) SetRange(el *marionette.WebElement, v float64) (actual float64, err error) {
+	str, err := s.SetInputValue(el, strconv.FormatFloat(v, 'f', -1, 64))
+	if err != nil {
+		return
+	}
+
+	return strconv.ParseFloat(str, 64)
+}
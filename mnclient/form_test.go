@@ -0,0 +1,49 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+func TestSelectArgs(t *testing.T) {
+	var sent []interface{}
+	cl := &Commander{Sender: &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		sent = cmd.(*mncmd.ExecuteScript).Args
+		return fakeValue(""), nil
+	}}}
+	el := &marionette.WebElement{Type: marionette.ElementType, UUID: "x"}
+
+	cases := []struct {
+		name   string
+		f      func() error
+		expect string
+	}{
+		{"clear", func() error { return cl.SelectByValue(el) }, `[]`},
+		{"value", func() error { return cl.SelectByValue(el, "a", "b", "a") }, `["a","b"]`},
+		{"text", func() error { return cl.SelectByText(el, "A", "A") }, `["A"]`},
+		{"index", func() error { return cl.SelectByIndex(el, 1, 0, 1) }, `[1,0]`},
+	}
+	for _, c := range cases {
+		if err := c.f(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.name, err)
+		}
+		buf, _ := json.Marshal(sent[2])
+		if string(buf) != c.expect {
+			t.Errorf("%s: unexpected wanted options: %s", c.name, buf)
+		}
+	}
+}
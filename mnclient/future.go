@@ -0,0 +1,245 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"errors"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// ErrNoResponse denotes the sender is closed before receiving the response
+var ErrNoResponse = errors.New("mnclient: no response, sender might be closed")
+
+// Future is a typed result which will be available later
+//
+// It is safe to await a Future multiple times, or from multiple goroutines.
+type Future[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+func newFuture[T any]() (ret *Future[T]) {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// Resolved creates a Future which is already settled with (val, err)
+func Resolved[T any](val T, err error) (ret *Future[T]) {
+	ret = newFuture[T]()
+	ret.settle(val, err)
+	return
+}
+
+// Go runs f in a new goroutine, and settles the Future with its result
+func Go[T any](f func() (T, error)) (ret *Future[T]) {
+	ret = newFuture[T]()
+	go func() {
+		ret.settle(f())
+	}()
+	return
+}
+
+func (f *Future[T]) settle(val T, err error) {
+	f.val, f.err = val, err
+	close(f.done)
+}
+
+// Done returns a channel which is closed when the Future is settled
+func (f *Future[T]) Done() (ret <-chan struct{}) {
+	return f.done
+}
+
+// Await waits the Future to be settled and returns its result
+//
+// It returns ctx.Err() if ctx is done first, the Future is not affected.
+func (f *Future[T]) Await(ctx context.Context) (ret T, err error) {
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		err = ctx.Err()
+		return
+	}
+}
+
+// All creates a Future which is settled with results of fs in same order
+//
+// It fails as soon as any of fs fails, with that error.
+func All[T any](fs ...*Future[T]) (ret *Future[[]T]) {
+	ret = newFuture[[]T]()
+	go func() {
+		failed := make(chan error, 1)
+		for _, f := range fs {
+			go func(f *Future[T]) {
+				<-f.done
+				if f.err != nil {
+					select {
+					case failed <- f.err:
+					default:
+					}
+				}
+			}(f)
+		}
+
+		vals := make([]T, len(fs))
+		for idx, f := range fs {
+			select {
+			case <-f.done:
+			case err := <-failed:
+				ret.settle(nil, err)
+				return
+			}
+			if f.err != nil {
+				ret.settle(nil, f.err)
+				return
+			}
+			vals[idx] = f.val
+		}
+		ret.settle(vals, nil)
+	}()
+	return
+}
+
+// Race creates a Future which is settled with the first settled one in fs,
+// regardless of success or failure
+//
+// Race without any Future never settles.
+func Race[T any](fs ...*Future[T]) (ret *Future[T]) {
+	ret = newFuture[T]()
+	won := make(chan *Future[T], len(fs))
+	for _, f := range fs {
+		go func(f *Future[T]) {
+			<-f.done
+			won <- f
+		}(f)
+	}
+	if len(fs) > 0 {
+		go func() {
+			f := <-won
+			ret.settle(f.val, f.err)
+		}()
+	}
+	return
+}
+
+// sendFuture sends cmd asynchronously and decodes the response with decode
+func sendFuture[T any](
+	s *Commander, cmd mncmd.Command, decode func(*marionette.Message) (T, error),
+) (ret *Future[T]) {
+	ch, err := s.Async(cmd)
+	if err != nil {
+		var zero T
+		return Resolved(zero, err)
+	}
+
+	return Go(func() (val T, err error) {
+		msg := <-ch
+		if msg == nil {
+			err = ErrNoResponse
+			return
+		}
+		return decode(msg)
+	})
+}
+
+// noValue decodes responses without value
+func noValue(msg *marionette.Message) (ret struct{}, err error) {
+	err = msg.Error
+	return
+}
+
+// Eval executes the script in default, mutable sandbox and decodes the result
+// as T
+//
+//	title, err := mnclient.Eval[string](cl, `return document.title`)
+func Eval[T any](s *Commander, script string, args ...interface{}) (ret T, err error) {
+	err = s.ExecuteScript(script, &ret, args...)
+	return
+}
+
+// EvalIn is like Eval, but executes the script in specified sandbox
+func EvalIn[T any](s *Commander, sandbox, script string, args ...interface{}) (ret T, err error) {
+	err = s.ExecuteScriptIn(sandbox, script, &ret, args...)
+	return
+}
+
+// EvalAsync is typed version of Commander.ExecuteAsyncScript
+//
+// The value passed to callback, which is always the last argument, is decoded
+// as T.
+func EvalAsync[T any](s *Commander, script string, args ...interface{}) (ret *Future[T]) {
+	return evalAsync[T](s, &mncmd.ExecuteAsyncScript{
+		Script: script,
+		Args:   args,
+	})
+}
+
+// EvalAsyncIn is typed version of Commander.ExecuteAsyncScriptIn
+func EvalAsyncIn[T any](
+	s *Commander, sandbox, script string, args ...interface{},
+) (ret *Future[T]) {
+	return evalAsync[T](s, &mncmd.ExecuteAsyncScript{
+		Script:       script,
+		Args:         args,
+		Sandbox:      sandbox,
+		ReuseSandbox: true,
+	})
+}
+
+func evalAsync[T any](s *Commander, cmd *mncmd.ExecuteAsyncScript) (ret *Future[T]) {
+	return sendFuture(s, cmd, func(msg *marionette.Message) (val T, err error) {
+		err = cmd.Decode(msg, &val)
+		return
+	})
+}
+
+// FindElementFuture is typed version of FindElementAsync
+func (s *Commander) FindElementFuture(
+	by marionette.FindStrategy, qstr string, root *marionette.WebElement,
+) (ret *Future[*marionette.WebElement]) {
+	cmd := &mncmd.FindElement{
+		Using:       by,
+		Value:       qstr,
+		RootElement: root,
+	}
+	return sendFuture(s, cmd, cmd.Decode)
+}
+
+// FindElementsFuture is typed version of FindElementsAsync
+func (s *Commander) FindElementsFuture(
+	by marionette.FindStrategy, qstr string, root *marionette.WebElement,
+) (ret *Future[[]*marionette.WebElement]) {
+	cmd := &mncmd.FindElements{
+		Using:       by,
+		Value:       qstr,
+		RootElement: root,
+	}
+	return sendFuture(s, cmd, cmd.Decode)
+}
+
+// NavigateFuture is typed version of NavigateAsync
+func (s *Commander) NavigateFuture(url string) (ret *Future[struct{}]) {
+	return sendFuture(s, &mncmd.Navigate{URL: url}, noValue)
+}
+
+// PerformActionsFuture is typed version of PerformActionsAsync
+//
+// Unlike PerformActionsAsync, errors returned by browser are reported.
+func (s *Commander) PerformActionsFuture(act marionette.ActionChain) (ret *Future[struct{}]) {
+	if err := act.Err(); err != nil {
+		return Resolved(struct{}{}, err)
+	}
+	return sendFuture(s, &mncmd.PerformActions{Actions: act}, noValue)
+}
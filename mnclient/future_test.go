@@ -0,0 +1,164 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// delayed creates a Future settled with (val, err) after d
+func delayed(d time.Duration, val int, err error) (ret *Future[int]) {
+	return Go(func() (int, error) {
+		time.Sleep(d)
+		return val, err
+	})
+}
+
+func TestFutureAwait(t *testing.T) {
+	f := delayed(10*time.Millisecond, 1, nil)
+	for i := 0; i < 2; i++ {
+		if v, err := f.Await(context.Background()); v != 1 || err != nil {
+			t.Fatalf("unexpected result #%d: %d, %v", i, v, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := delayed(time.Second, 1, nil).Await(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFutureAll(t *testing.T) {
+	ctx := context.Background()
+	vals, err := All(
+		delayed(20*time.Millisecond, 1, nil),
+		delayed(0, 2, nil),
+		Resolved(3, nil),
+	).Await(ctx)
+	if err != nil || !reflect.DeepEqual(vals, []int{1, 2, 3}) {
+		t.Fatalf("unexpected result: %v, %v", vals, err)
+	}
+
+	// fails as soon as possible
+	e := errors.New("failed")
+	begin := time.Now()
+	_, err = All(
+		delayed(time.Second, 1, nil),
+		delayed(10*time.Millisecond, 2, e),
+	).Await(ctx)
+	if err != e {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Since(begin) > 500*time.Millisecond {
+		t.Fatal("All does not fail fast")
+	}
+
+	vals, err = All[int]().Await(ctx)
+	if err != nil || len(vals) != 0 {
+		t.Fatalf("unexpected result of empty All: %v, %v", vals, err)
+	}
+}
+
+func TestFutureRace(t *testing.T) {
+	ctx := context.Background()
+	v, err := Race(
+		delayed(time.Second, 1, nil),
+		delayed(10*time.Millisecond, 2, nil),
+	).Await(ctx)
+	if v != 2 || err != nil {
+		t.Fatalf("unexpected result: %d, %v", v, err)
+	}
+
+	e := errors.New("failed")
+	_, err = Race(
+		delayed(time.Second, 1, nil),
+		delayed(0, 2, e),
+	).Await(ctx)
+	if err != e {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEval(t *testing.T) {
+	cl := &Commander{Sender: &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		switch cmd.(type) {
+		case *mncmd.ExecuteScript:
+			return fakeValue([]interface{}{1, 2}), nil
+		case *mncmd.ExecuteAsyncScript:
+			return fakeValue(map[string]interface{}{marionette.ElementType: "x"}), nil
+		}
+		return nil, &marionette.ErrDriver{Type: marionette.ErrUnknownCommand}
+	}}}
+
+	nums, err := Eval[[]int](cl, `return [1, 2]`)
+	if err != nil || !reflect.DeepEqual(nums, []int{1, 2}) {
+		t.Fatalf("unexpected result of Eval: %v, %v", nums, err)
+	}
+
+	el, err := EvalAsync[*marionette.WebElement](cl, `arguments[0](document.body)`).
+		Await(context.Background())
+	if err != nil || el == nil || el.UUID != "x" {
+		t.Fatalf("unexpected result of EvalAsync: %+v, %v", el, err)
+	}
+
+	_, err = Eval[string](cl, `return [1, 2]`)
+	if err == nil {
+		t.Fatal("expected decoding error")
+	}
+}
+
+func TestCommanderFutures(t *testing.T) {
+	cl := &Commander{Sender: &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		switch cmd.(type) {
+		case *mncmd.FindElement:
+			return fakeElem("a"), nil
+		case *mncmd.FindElements:
+			return []interface{}{
+				map[string]string{marionette.ElementType: "a"},
+				map[string]string{marionette.ElementType: "b"},
+			}, nil
+		case *mncmd.PerformActions:
+			return nil, &marionette.ErrDriver{Type: marionette.ErrInvalidArgument}
+		}
+		return nil, nil
+	}}}
+	ctx := context.Background()
+
+	el, err := cl.FindElementFuture(marionette.ID, "a", nil).Await(ctx)
+	if err != nil || el.UUID != "a" {
+		t.Fatalf("unexpected element: %+v, %v", el, err)
+	}
+
+	els, err := cl.FindElementsFuture(marionette.TagName, "a", nil).Await(ctx)
+	if err != nil || len(els) != 2 || els[1].UUID != "b" {
+		t.Fatalf("unexpected elements: %+v, %v", els, err)
+	}
+
+	if _, err = cl.NavigateFuture("about:blank").Await(ctx); err != nil {
+		t.Fatalf("unexpected error of Navigate: %v", err)
+	}
+
+	var chain marionette.ActionChain
+	chain.KeyDown("a")
+	if _, err = cl.PerformActionsFuture(chain).Await(ctx); err == nil {
+		t.Fatal("error of PerformActions is not reported")
+	}
+}
@@ -0,0 +1,74 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"math"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+const jsViewportCenter = `
+const el = arguments[0];
+el.scrollIntoView({block: "nearest", inline: "nearest"});
+const r = el.getBoundingClientRect();
+return {
+  X: r.left + r.width / 2, Y: r.top + r.height / 2,
+  W: document.documentElement.clientWidth, H: document.documentElement.clientHeight,
+};
+`
+
+// viewportCenter returns center of el and size of viewport
+func (s *Commander) viewportCenter(el *marionette.WebElement) (
+	center, size marionette.Point, err error,
+) {
+	var p struct{ X, Y, W, H float64 }
+	if err = s.ExecuteScript(jsViewportCenter, &p, el); err == nil {
+		center = marionette.Point{X: int(math.Round(p.X)), Y: int(math.Round(p.Y))}
+		size = marionette.Point{X: int(p.W), Y: int(p.H)}
+	}
+	return
+}
+
+// ViewportCenter scrolls el into view if needed, and returns its center
+// relative to viewport
+func (s *Commander) ViewportCenter(el *marionette.WebElement) (ret marionette.Point, err error) {
+	ret, _, err = s.viewportCenter(el)
+	return
+}
+
+// HumanMoveToElement adds a HumanPath from current position of src to center of
+// el (plus the offset)
+//
+// Position of el is computed when calling this method, so do not scroll the page
+// before performing the actions. opts.Bounds is set to size of viewport if not
+// specified. Like PointerSource.HumanMoveTo, current position of src must be
+// known.
+func (s *Commander) HumanMoveToElement(
+	src *marionette.PointerSource, el *marionette.WebElement,
+	offsetX, offsetY int, opts *marionette.PathOptions,
+) (err error) {
+	p, size, err := s.viewportCenter(el)
+	if err != nil {
+		return
+	}
+
+	var o marionette.PathOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Bounds == (marionette.Point{}) {
+		o.Bounds = size
+	}
+	src.HumanMoveTo(p.X+offsetX, p.Y+offsetY, &o)
+	return
+}
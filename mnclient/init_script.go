@@ -0,0 +1,191 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// InitScriptScope denotes where an init script runs
+type InitScriptScope int
+
+const (
+	// runs in current tab only
+	InitScriptTab InitScriptScope = iota
+	// runs in every tab, including tabs opened later
+	InitScriptBrowser
+)
+
+// InitScript is a script registered by AddInitScript
+type InitScript struct {
+	ID string
+	// window handle of the tab, empty for InitScriptBrowser
+	Tab string
+	// url of the frame script
+	url string
+}
+
+// message sent to frame scripts to stop running init scripts
+const initScriptRemoveMsg = "mnclient:init-script:remove"
+
+// frame script running the init script, placeholders are replaced with json
+const jsInitFrameScript = `(() => {
+const Cu = Components.utils;
+const [id, src] = %ARGS%;
+const onCreated = e => {
+  const win = e.target.defaultView;
+  if (!win) return;
+  const sb = Cu.Sandbox(win, {
+    sandboxPrototype: win,
+    wantXrays: false,
+    sandboxName: "mnclient-init-script-" + id,
+  });
+  try {
+    Cu.evalInSandbox(src, sb, null, "mnclient-init-script-" + id + ".js", 1);
+  } catch (err) {
+    Cu.reportError(err);
+  }
+};
+const onRemove = msg => {
+  if (msg.data.id !== id) return;
+  removeEventListener("DOMWindowCreated", onCreated, true);
+  removeMessageListener("` + initScriptRemoveMsg + `", onRemove);
+};
+addEventListener("DOMWindowCreated", onCreated, true);
+addMessageListener("` + initScriptRemoveMsg + `", onRemove);
+})();
+`
+
+// finds the <browser> of a tab by its window handle
+const jsFindBrowser = `
+const findBrowser = handle => {
+  for (const win of Services.wm.getEnumerator("navigator:browser")) {
+    for (const b of win.gBrowser.browsers) {
+      if (String(b.browserId) === handle || String(b.outerWindowID) === handle) {
+        return b;
+      }
+    }
+  }
+  return null;
+};
+`
+
+const jsAddInitScript = jsFindBrowser + `
+const [url, handle] = arguments;
+if (!handle) {
+  Services.mm.loadFrameScript(url, true);
+  return true;
+}
+const b = findBrowser(handle);
+if (!b) return false;
+b.messageManager.loadFrameScript(url, true);
+return true;
+`
+
+const jsRemoveInitScript = jsFindBrowser + `
+const [url, handle, id] = arguments;
+if (!handle) {
+  Services.mm.removeDelayedFrameScript(url);
+  Services.mm.broadcastAsyncMessage("` + initScriptRemoveMsg + `", {id});
+  return;
+}
+const b = findBrowser(handle);
+if (!b) return;
+b.messageManager.removeDelayedFrameScript(url);
+b.messageManager.sendAsyncMessage("` + initScriptRemoveMsg + `", {id});
+`
+
+// newScriptID generates random id of frame scripts
+func newScriptID() (ret string, err error) {
+	buf := make([]byte, 8)
+	if _, err = rand.Read(buf); err == nil {
+		ret = hex.EncodeToString(buf)
+	}
+	return
+}
+
+// ErrTabNotFound denotes the tab of current window handle cannot be found in
+// chrome context
+var ErrTabNotFound = errors.New("mnclient: cannot find the tab of current window")
+
+// AddInitScript registers js to run on every page load, before page scripts
+//
+// The script runs when a window (including iframes in same process) is created,
+// in a sandbox which uses the page window as prototype and shares its
+// principal. It is not affected by Content Security Policy.
+//
+// As the sandbox has its own global object, bare assignments and top-level
+// declarations ("setTimeout = fake", "var x", "function f() {}") are not
+// visible to the page. Set properties of window explicitly to modify globals:
+//
+//	window.setTimeout = fake;
+//	window.x = 1;
+//
+// It is implemented with frame scripts in chrome context, which takes effect
+// from next navigation: current page is not affected. Cross-process iframes
+// (with Fission enabled) are not covered.
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (s *Commander) AddInitScript(js string, scope InitScriptScope) (ret *InitScript, err error) {
+	id, err := newScriptID()
+	if err != nil {
+		return
+	}
+	script := &InitScript{ID: id}
+
+	if scope == InitScriptTab {
+		if script.Tab, err = s.GetWindowHandle(); err != nil {
+			return
+		}
+	}
+
+	args, err := json.Marshal([]string{script.ID, js})
+	if err != nil {
+		return
+	}
+	script.url = "data:application/javascript," + url.PathEscape(
+		strings.Replace(jsInitFrameScript, "%ARGS%", string(args), 1),
+	)
+
+	var found bool
+	err = s.runInChrome(func() error {
+		return s.ExecuteScript(jsAddInitScript, &found, script.url, script.Tab)
+	})
+	if err == nil && !found {
+		err = ErrTabNotFound
+	}
+	if err != nil {
+		return
+	}
+	return script, nil
+}
+
+// RemoveInitScript stops running the script registered by AddInitScript
+//
+// Pages already loaded are not affected. Removing an init script of closed tab
+// is a no-op.
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (s *Commander) RemoveInitScript(script *InitScript) (err error) {
+	return s.runInChrome(func() error {
+		return s.ExecuteScript(
+			jsRemoveInitScript, nil, script.url, script.Tab, script.ID,
+		)
+	})
+}
@@ -0,0 +1,113 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// fakeChrome responds context commands and records script args
+type fakeChrome struct {
+	ctx   string
+	found bool
+	args  [][]interface{}
+}
+
+func (f *fakeChrome) handle(cmd mncmd.Command) (interface{}, error) {
+	switch c := cmd.(type) {
+	case *mncmd.GetWindowHandle:
+		return fakeValue("42"), nil
+	case *mncmd.MozGetContext:
+		return fakeValue(f.ctx), nil
+	case *mncmd.MozSetContext:
+		f.ctx = c.Context
+		return nil, nil
+	case *mncmd.ExecuteScript:
+		if f.ctx != marionette.ChromeContext {
+			return nil, &marionette.ErrDriver{Type: marionette.ErrJavascriptError}
+		}
+		f.args = append(f.args, c.Args)
+		return fakeValue(f.found), nil
+	}
+	return nil, &marionette.ErrDriver{Type: marionette.ErrUnknownCommand}
+}
+
+func TestAddInitScript(t *testing.T) {
+	f := &fakeChrome{ctx: marionette.ContentContext, found: true}
+	s := &fakeSender{handler: f.handle}
+	cl := &Commander{Sender: s}
+
+	script, err := cl.AddInitScript(`window.x = "</script>"`, InitScriptTab)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f.ctx != marionette.ContentContext {
+		t.Fatalf("context is not restored: %s", f.ctx)
+	}
+	if script.Tab != "42" || script.ID == "" {
+		t.Fatalf("unexpected script: %+v", script)
+	}
+
+	u, _ := f.args[0][0].(string)
+	src, err := url.PathUnescape(strings.TrimPrefix(u, "data:application/javascript,"))
+	if err != nil {
+		t.Fatalf("invalid frame script url: %s", err)
+	}
+	if !strings.Contains(src, `["`+script.ID+`","window.x = \"\u003c/script\u003e\""]`) {
+		t.Fatalf("script is not embedded in frame script:\n%s", src)
+	}
+	if !reflect.DeepEqual(f.args[0][1:], []interface{}{"42"}) {
+		t.Fatalf("unexpected args: %v", f.args[0])
+	}
+
+	if err = cl.RemoveInitScript(script); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expect := []interface{}{u, "42", script.ID}
+	if !reflect.DeepEqual(f.args[1], expect) {
+		t.Fatalf("unexpected args of remove: %v", f.args[1])
+	}
+}
+
+func TestAddInitScriptBrowser(t *testing.T) {
+	f := &fakeChrome{ctx: marionette.ContentContext, found: true}
+	s := &fakeSender{handler: f.handle}
+	cl := &Commander{Sender: s}
+
+	script, err := cl.AddInitScript(`1`, InitScriptBrowser)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if script.Tab != "" {
+		t.Fatalf("unexpected tab: %s", script.Tab)
+	}
+	for _, c := range s.commands() {
+		if c == "WebDriver:GetWindowHandle" {
+			t.Fatal("browser-wide script should not get window handle")
+		}
+	}
+}
+
+func TestAddInitScriptNoTab(t *testing.T) {
+	f := &fakeChrome{ctx: marionette.ContentContext}
+	cl := &Commander{Sender: &fakeSender{handler: f.handle}}
+
+	if _, err := cl.AddInitScript(`1`, InitScriptTab); err != ErrTabNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
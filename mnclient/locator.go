@@ -0,0 +1,375 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// Locator is a composable element lookup
+//
+// A Locator starts with a query (CSS, XPath, Text...), and can be refined by
+// filters and scoped by another Locator:
+//
+//	// 3rd form containing text "Login", inside #main
+//	loc := CSS("form").Has(Text("Login")).Nth(2).Within(CSS("#main"))
+//	el, err := cl.Locate(loc)
+//
+// Filters are applied in the order they are added. Locators are immutable, every
+// method returns a new Locator, so it is safe to share and reuse them.
+//
+// A Locator with no filter and no scope is sent as plain FindElement(s) command,
+// others are compiled into one injected query script.
+type Locator struct {
+	by      marionette.FindStrategy
+	qstr    string
+	within  *Locator
+	filters []locFilter
+}
+
+type locFilter struct {
+	Type  string   `json:"type"`
+	Value string   `json:"value,omitempty"`
+	N     int      `json:"n,omitempty"`
+	Sub   *Locator `json:"sub,omitempty"`
+}
+
+// strategies which can be handled by injected script
+var locStrategies = map[marionette.FindStrategy]bool{
+	marionette.ClassName:       true,
+	marionette.Selector:        true,
+	marionette.ID:              true,
+	marionette.Name:            true,
+	marionette.LinkText:        true,
+	marionette.PartialLinkText: true,
+	marionette.TagName:         true,
+	marionette.XPath:           true,
+	locText:                    true,
+	locExactText:               true,
+}
+
+const (
+	locText      marionette.FindStrategy = "text"
+	locExactText marionette.FindStrategy = "exact text"
+)
+
+// By creates a Locator from FindStrategy
+//
+// Anon and AnonAttribute cannot be used with filters or scopes.
+func By(by marionette.FindStrategy, qstr string) (ret *Locator) {
+	return &Locator{by: by, qstr: qstr}
+}
+
+// CSS creates a Locator which finds elements by css selector
+func CSS(selector string) (ret *Locator) {
+	return By(marionette.Selector, selector)
+}
+
+// XPath creates a Locator which finds elements by xpath expression
+func XPath(expr string) (ret *Locator) {
+	return By(marionette.XPath, expr)
+}
+
+// Text creates a Locator which finds innermost elements containing text
+//
+// Whitespaces are normalized before comparing.
+func Text(text string) (ret *Locator) {
+	return By(locText, text)
+}
+
+// ExactText is like Text, but text content must be identical
+func ExactText(text string) (ret *Locator) {
+	return By(locExactText, text)
+}
+
+func (l *Locator) clone() (ret *Locator) {
+	x := *l
+	x.filters = append([]locFilter(nil), l.filters...)
+	return &x
+}
+
+func (l *Locator) filter(f locFilter) (ret *Locator) {
+	ret = l.clone()
+	ret.filters = append(ret.filters, f)
+	return
+}
+
+// Has keeps elements which contain an element matching sub
+func (l *Locator) Has(sub *Locator) (ret *Locator) {
+	return l.filter(locFilter{Type: "has", Sub: sub})
+}
+
+// HasNot keeps elements which contain no element matching sub
+func (l *Locator) HasNot(sub *Locator) (ret *Locator) {
+	return l.filter(locFilter{Type: "hasNot", Sub: sub})
+}
+
+// HasText keeps elements whose text content contains text
+func (l *Locator) HasText(text string) (ret *Locator) {
+	return l.filter(locFilter{Type: "text", Value: text})
+}
+
+// Visible keeps elements which are rendered and not hidden
+func (l *Locator) Visible() (ret *Locator) {
+	return l.filter(locFilter{Type: "visible"})
+}
+
+// Nth keeps only the n-th (0-based) element, negative n counts from the end
+func (l *Locator) Nth(n int) (ret *Locator) {
+	return l.filter(locFilter{Type: "nth", N: n})
+}
+
+// First is identical to Nth(0)
+func (l *Locator) First() (ret *Locator) {
+	return l.Nth(0)
+}
+
+// Last is identical to Nth(-1)
+func (l *Locator) Last() (ret *Locator) {
+	return l.Nth(-1)
+}
+
+// Within limits the lookup to descendants of elements matching parent
+//
+// If l is already scoped, parent is applied to the outermost scope.
+func (l *Locator) Within(parent *Locator) (ret *Locator) {
+	ret = l.clone()
+	if l.within == nil {
+		ret.within = parent
+		return
+	}
+	ret.within = l.within.Within(parent)
+	return
+}
+
+// Find creates a Locator which finds child inside elements matching l
+//
+// It is identical to child.Within(l).
+func (l *Locator) Find(child *Locator) (ret *Locator) {
+	return child.Within(l)
+}
+
+// simple reports whether l can be sent as plain FindElement(s) command
+func (l *Locator) simple() (ok bool) {
+	return l.within == nil && len(l.filters) == 0 &&
+		l.by != locText && l.by != locExactText
+}
+
+func (l *Locator) validate() (ok bool) {
+	if !l.simple() && !locStrategies[l.by] {
+		return false
+	}
+	if l.within != nil && !l.within.validate() {
+		return false
+	}
+	for _, f := range l.filters {
+		if f.Sub != nil && !f.Sub.validate() {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns human readable representation of l
+func (l *Locator) String() (ret string) {
+	var b strings.Builder
+	if l.within != nil {
+		b.WriteString(l.within.String())
+		b.WriteString(" >> ")
+	}
+	b.WriteString(string(l.by))
+	b.WriteString("(" + strconv.Quote(l.qstr) + ")")
+	for _, f := range l.filters {
+		switch f.Type {
+		case "has", "hasNot":
+			b.WriteString("." + f.Type + "(" + f.Sub.String() + ")")
+		case "text":
+			b.WriteString(".hasText(" + strconv.Quote(f.Value) + ")")
+		case "nth":
+			b.WriteString(".nth(" + strconv.Itoa(f.N) + ")")
+		default:
+			b.WriteString("." + f.Type + "()")
+		}
+	}
+	return b.String()
+}
+
+func (l *Locator) MarshalJSON() (data []byte, err error) {
+	return json.Marshal(map[string]interface{}{
+		"by":      l.by,
+		"value":   l.qstr,
+		"within":  l.within,
+		"filters": l.filters,
+	})
+}
+
+const jsLocate = `
+const [spec, root] = arguments;
+const norm = s => (s || "").replace(/\s+/g, " ").trim();
+const byText = (scope, test) => Array.from(scope.querySelectorAll("*")).filter(
+  el => !["SCRIPT", "STYLE"].includes(el.tagName) && test(norm(el.textContent)) &&
+    !Array.from(el.children).some(c => test(norm(c.textContent)))
+);
+const query = (by, v, scope) => {
+  switch (by) {
+  case "css selector": return Array.from(scope.querySelectorAll(v));
+  case "id": return Array.from(scope.querySelectorAll('[id="' + CSS.escape(v) + '"]'));
+  case "name": return Array.from(scope.querySelectorAll('[name="' + CSS.escape(v) + '"]'));
+  case "class name": return Array.from(scope.querySelectorAll("." + CSS.escape(v)));
+  case "tag name": return Array.from(scope.querySelectorAll(v));
+  case "link text": return Array.from(scope.querySelectorAll("a")).filter(a => norm(a.textContent) === v);
+  case "partial link text": return Array.from(scope.querySelectorAll("a")).filter(a => norm(a.textContent).includes(v));
+  case "text": return byText(scope, t => t.includes(norm(v)));
+  case "exact text": return byText(scope, t => t === norm(v));
+  case "xpath": {
+    const r = document.evaluate(v, scope, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+    const ret = [];
+    for (let i = 0; i < r.snapshotLength; i++) {
+      const n = r.snapshotItem(i);
+      if (n.nodeType === Node.ELEMENT_NODE) ret.push(n);
+    }
+    return ret;
+  }
+  }
+  throw new Error("unsupported strategy: " + by);
+};
+` + jsVisible + `
+const resolve = (spec, scopes) => {
+  if (spec.within) scopes = resolve(spec.within, scopes);
+  const set = new Set();
+  for (const s of scopes) for (const el of query(spec.by, spec.value, s)) set.add(el);
+  let ret = Array.from(set).sort(
+    (a, b) => a.compareDocumentPosition(b) & Node.DOCUMENT_POSITION_FOLLOWING ? -1 : 1
+  );
+  for (const f of spec.filters || []) {
+    switch (f.type) {
+    case "has": ret = ret.filter(el => resolve(f.sub, [el]).length > 0); break;
+    case "hasNot": ret = ret.filter(el => resolve(f.sub, [el]).length === 0); break;
+    case "text": ret = ret.filter(el => norm(el.textContent).includes(norm(f.value))); break;
+    case "visible": ret = ret.filter(visible); break;
+    case "nth": {
+      const n = f.n || 0;
+      const el = ret[n < 0 ? ret.length + n : n];
+      ret = el ? [el] : [];
+      break;
+    }
+    }
+  }
+  return ret;
+};
+return resolve(spec, [root || document]);
+`
+
+func (s *Commander) locate(l *Locator, root *marionette.WebElement) (
+	ret []*marionette.WebElement, err error,
+) {
+	if !l.validate() {
+		err = errors.New("mnclient: invalid locator " + l.String())
+		return
+	}
+	if l.simple() {
+		return s.FindElements(l.by, l.qstr, root)
+	}
+
+	err = s.ExecuteScript(jsLocate, &ret, l, root)
+	return
+}
+
+func (s *Commander) locateOne(l *Locator, root *marionette.WebElement) (
+	ret *marionette.WebElement, err error,
+) {
+	if l.simple() && l.validate() {
+		return s.FindElement(l.by, l.qstr, root)
+	}
+
+	els, err := s.locate(l, root)
+	if err != nil {
+		return
+	}
+	if len(els) == 0 {
+		err = &marionette.ErrDriver{
+			Type:    marionette.ErrNoSuchElement,
+			Message: "no element matches " + l.String(),
+		}
+		return
+	}
+
+	return els[0], nil
+}
+
+// LocateElement finds first element matching l, inside root if not nil
+//
+// Like FindElement, it returns ErrNoSuchElement if nothing matches.
+func (s *Commander) LocateElement(l *Locator, root *marionette.WebElement) (
+	ret *marionette.WebElement, err error,
+) {
+	return s.locateOne(l, root)
+}
+
+// LocateElements finds all elements matching l, inside root if not nil
+func (s *Commander) LocateElements(l *Locator, root *marionette.WebElement) (
+	ret []*marionette.WebElement, err error,
+) {
+	return s.locate(l, root)
+}
+
+// Locate is identical to LocateElement(l, nil), but returns Element
+func (s *Commander) Locate(l *Locator) (ret *Element, err error) {
+	el, err := s.locateOne(l, nil)
+	if err == nil {
+		ret = NewElement(s, el)
+	}
+	return
+}
+
+// LocateAll is identical to LocateElements(l, nil), but returns Elements
+func (s *Commander) LocateAll(l *Locator) (ret []*Element, err error) {
+	els, err := s.locate(l, nil)
+	if err == nil {
+		ret = newElements(s, els)
+	}
+	return
+}
+
+// Locate finds first element matching l inside this element
+func (e *Element) Locate(l *Locator) (ret *Element, err error) {
+	el, err := e.Commander.locateOne(l, e.WebElement)
+	if err == nil {
+		ret = NewElement(e.Commander, el)
+	}
+	return
+}
+
+// LocateAll finds all elements matching l inside this element
+func (e *Element) LocateAll(l *Locator) (ret []*Element, err error) {
+	els, err := e.Commander.locate(l, e.WebElement)
+	if err == nil {
+		ret = newElements(e.Commander, els)
+	}
+	return
+}
+
+// Located is like ElementPresent, but uses Locator
+func Located(l *Locator) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		_, err = cl.locateOne(l, nil)
+		if isRecoverable(err) {
+			return false, "element " + l.String() + " not found", nil
+		}
+		return err == nil, "", err
+	}
+}
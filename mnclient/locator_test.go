@@ -0,0 +1,98 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+func TestLocatorImmutable(t *testing.T) {
+	base := CSS("form")
+	a := base.Nth(1)
+	b := base.Visible()
+
+	if len(base.filters) != 0 || len(a.filters) != 1 || len(b.filters) != 1 {
+		t.Fatal("filters are shared between locators")
+	}
+	if a.filters[0].Type != "nth" || b.filters[0].Type != "visible" {
+		t.Fatalf("unexpected filters: %+v, %+v", a.filters, b.filters)
+	}
+}
+
+func TestLocatorJSON(t *testing.T) {
+	l := CSS("button").Has(Text("OK")).Nth(-1).Within(CSS("#main")).Within(XPath("//body"))
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatalf("cannot marshal: %s", err)
+	}
+	var actual interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("cannot unmarshal: %s", err)
+	}
+
+	var expected interface{}
+	json.Unmarshal([]byte(`{
+  "by": "css selector", "value": "button",
+  "filters": [
+    {"type": "has", "sub": {"by": "text", "value": "OK", "filters": null, "within": null}},
+    {"type": "nth", "n": -1}
+  ],
+  "within": {
+    "by": "css selector", "value": "#main", "filters": null,
+    "within": {"by": "xpath", "value": "//body", "filters": null, "within": null}
+  }
+}`), &expected)
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("unexpected result: %s", data)
+	}
+
+	str := `xpath("//body") >> css selector("#main") >> css selector("button").has(text("OK")).nth(-1)`
+	if l.String() != str {
+		t.Fatalf("unexpected string: %s", l)
+	}
+}
+
+func TestLocatorCalls(t *testing.T) {
+	sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		switch cmd.(type) {
+		case *mncmd.FindElement:
+			return fakeElem("a"), nil
+		case *mncmd.ExecuteScript:
+			return fakeValue([]map[string]string{}), nil
+		}
+		return nil, &marionette.ErrDriver{Type: marionette.ErrUnknownCommand}
+	}}
+	cl := &Commander{Sender: sender}
+
+	if _, err := cl.Locate(CSS("form")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err := cl.Locate(CSS("form").First())
+	if e, ok := err.(*marionette.ErrDriver); !ok || e.Type != marionette.ErrNoSuchElement {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cl.Locate(By(marionette.Anon, "x").First()); err == nil {
+		t.Fatal("expected error for unsupported strategy")
+	}
+
+	expected := []string{"WebDriver:FindElement", "WebDriver:ExecuteScript"}
+	if cmds := sender.commands(); !reflect.DeepEqual(cmds, expected) {
+		t.Fatalf("unexpected commands: %v", cmds)
+	}
+}
@@ -0,0 +1,147 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+// maps Permissions API names to Firefox permission types
+var mozPermTypes = map[string]string{
+	"geolocation":        "geo",
+	"notifications":      "desktop-notification",
+	"camera":             "camera",
+	"microphone":         "microphone",
+	"persistent-storage": "persistent-storage",
+}
+
+func mozPermType(name string) (ret string) {
+	if ret = mozPermTypes[name]; ret == "" {
+		ret = name
+	}
+	return
+}
+
+const jsSetPermission = `
+const [origin, type, state] = arguments;
+const ssm = Services.scriptSecurityManager;
+const principal = ssm.createContentPrincipalFromOrigin ?
+  ssm.createContentPrincipalFromOrigin(origin) :
+  ssm.createCodebasePrincipalFromOrigin(origin);
+if (state === "") {
+  Services.perms.removeFromPrincipal(principal, type);
+  return;
+}
+const action = {
+  granted: Services.perms.ALLOW_ACTION,
+  denied: Services.perms.DENY_ACTION,
+  prompt: Services.perms.PROMPT_ACTION,
+}[state];
+if (action === undefined) {
+  throw new Error("unknown permission state: " + state);
+}
+Services.perms.addFromPrincipal(principal, type, action);
+`
+
+// SetPermissionFor sets permission state for specified origin
+//
+// Unlike SetPermission, it manipulates Firefox permission manager in chrome
+// context, so it works before the page is loaded. The origin is scheme, host and
+// port like "https://example.com:8443".
+//
+// Names defined in Permissions API ("geolocation", "notifications", "camera",
+// ...) are translated into corresponding Firefox permission type, other names are
+// passed as-is.
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (s *Commander) SetPermissionFor(origin, name, state string) (err error) {
+	return s.runInChrome(func() error {
+		return s.ExecuteScript(
+			jsSetPermission, nil, origin, mozPermType(name), state,
+		)
+	})
+}
+
+// ResetPermissionFor removes permission setting of specified origin
+//
+// See SetPermissionFor for details.
+func (s *Commander) ResetPermissionFor(origin, name string) (err error) {
+	return s.SetPermissionFor(origin, name, "")
+}
+
+const jsSetPrefs = `
+const prefs = arguments[0];
+for (const [k, v] of Object.entries(prefs)) {
+  if (v === null) {
+    Services.prefs.clearUserPref(k);
+    continue;
+  }
+  switch (typeof v) {
+    case "boolean":
+      Services.prefs.setBoolPref(k, v);
+      break;
+    case "number":
+      Services.prefs.setIntPref(k, v);
+      break;
+    default:
+      Services.prefs.setStringPref(k, v);
+  }
+}
+`
+
+// SetGeolocation overrides position reported to navigator.geolocation
+//
+// It replaces network geolocation provider with a fixed response, which is
+// browser-wide. Pages still need permission to read the position, see
+// SetPermissionFor.
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (s *Commander) SetGeolocation(lat, lng, accuracy float64) (err error) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"location": map[string]float64{"lat": lat, "lng": lng},
+		"accuracy": accuracy,
+	})
+	prefs := map[string]interface{}{
+		"geo.provider.network.url":         "data:application/json," + url.PathEscape(string(data)),
+		"geo.provider.testing":             true,
+		"geo.provider.use_corelocation":    false,
+		"geo.provider.use_geoclue":         false,
+		"geo.provider.use_gpsd":            false,
+		"geo.provider.ms-windows-location": false,
+	}
+
+	return s.runInChrome(func() error {
+		return s.ExecuteScript(jsSetPrefs, nil, prefs)
+	})
+}
+
+// ClearGeolocation removes the override set by SetGeolocation
+//
+// It switches to chrome context temporarily, see "Chrome context" in package
+// document.
+func (s *Commander) ClearGeolocation() (err error) {
+	prefs := map[string]interface{}{
+		"geo.provider.network.url":         nil,
+		"geo.provider.testing":             nil,
+		"geo.provider.use_corelocation":    nil,
+		"geo.provider.use_geoclue":         nil,
+		"geo.provider.use_gpsd":            nil,
+		"geo.provider.ms-windows-location": nil,
+	}
+
+	return s.runInChrome(func() error {
+		return s.ExecuteScript(jsSetPrefs, nil, prefs)
+	})
+}
@@ -0,0 +1,421 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// StepType denotes type of a RecordedStep
+type StepType string
+
+// supported step types
+const (
+	// loads Value as URL
+	StepNavigate StepType = "navigate"
+	// clicks element
+	StepClick StepType = "click"
+	// clears the input and types Value into it
+	StepFill StepType = "fill"
+	// selects options by Values
+	StepSelect StepType = "select"
+	// presses key combination in Value, see marionette.ParseShortcut
+	StepKey StepType = "key"
+	// scrolls the window to (X, Y)
+	StepScroll StepType = "scroll"
+)
+
+// RecordedStep is an interaction captured by Recorder
+type RecordedStep struct {
+	Type StepType `json:"type"`
+	// css selector of the target element
+	Selector string   `json:"selector,omitempty"`
+	Value    string   `json:"value,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	X        int      `json:"x,omitempty"`
+	Y        int      `json:"y,omitempty"`
+	// when the step is captured, in milliseconds since unix epoch
+	Time float64 `json:"t,omitempty"`
+}
+
+// Recording is a list of steps, which can be replayed or converted to Go code
+type Recording []RecordedStep
+
+// sandbox keeping listeners of recorder
+const recorderSandbox = "mnclient-recorder"
+
+// navigations started within this duration after an user event are treated as
+// result of that event, and are not recorded
+const recorderNavGap = 1000
+
+// RecordedPassword is recorded as Value of StepFill in place of text typed into
+// password fields
+const RecordedPassword = "<password>"
+
+const jsRecorder = `
+const KEY = "mnclient-recorder";
+const PASSWORD = "` + RecordedPassword + `";
+const store = {
+  load() {
+    try {
+      return JSON.parse(sessionStorage.getItem(KEY) || "[]");
+    } catch (e) {
+      return globalThis.mnRecEvents || [];
+    }
+  },
+  save(list) {
+    try {
+      sessionStorage.setItem(KEY, JSON.stringify(list));
+    } catch (e) {
+      globalThis.mnRecEvents = list;
+    }
+  },
+};
+
+const selector = el => {
+  const parts = [];
+  for (; el && el.nodeType === 1; el = el.parentElement) {
+    if (el.id) {
+      parts.unshift("#" + CSS.escape(el.id));
+      break;
+    }
+    let s = el.localName;
+    const name = el.getAttribute("name");
+    if (name) s += '[name="' + name.replace(/["\\]/g, "\\$&") + '"]';
+    const p = el.parentElement;
+    if (p && [...p.children].filter(x => x.localName === el.localName).length > 1) {
+      s += ":nth-of-type(" +
+        ([...p.children].filter(x => x.localName === el.localName).indexOf(el) + 1) + ")";
+    }
+    parts.unshift(s);
+    if (document.querySelectorAll(parts.join(" > ")).length === 1) break;
+  }
+  return parts.join(" > ");
+};
+
+const push = ev => {
+  ev.t = Date.now();
+  const list = store.load();
+  const last = list[list.length - 1];
+  if (last && last.type === ev.type && last.selector === ev.selector &&
+      (ev.type === "fill" || ev.type === "scroll")) {
+    list.pop();
+  }
+  list.push(ev);
+  store.save(list);
+};
+
+const textual = el => el.localName === "textarea" || (el.localName === "input" &&
+  !["checkbox", "radio", "button", "submit", "reset", "file", "image"].includes(el.type));
+
+const install = () => {
+  document.addEventListener("click", e => {
+    if (!e.isTrusted || !e.target.closest) return;
+    // selects are recorded by "change" event
+    if (e.target.closest("select")) return;
+    const el = e.target.closest("a,button,input,label,textarea,summary,[role=button],[onclick]") || e.target;
+    push({type: "click", selector: selector(el)});
+  }, true);
+
+  document.addEventListener("input", e => {
+    if (!e.isTrusted || !textual(e.target)) return;
+    const value = e.target.type === "password" ? PASSWORD : e.target.value;
+    push({type: "fill", selector: selector(e.target), value});
+  }, true);
+
+  document.addEventListener("change", e => {
+    if (!e.isTrusted || e.target.localName !== "select") return;
+    const values = [...e.target.selectedOptions].map(o => o.value);
+    push({type: "select", selector: selector(e.target), values});
+  }, true);
+
+  const names = {" ": "Space", "+": "Plus"};
+  document.addEventListener("keydown", e => {
+    if (!e.isTrusted || ["Control", "Shift", "Alt", "Meta"].includes(e.key)) return;
+    const mod = e.ctrlKey || e.altKey || e.metaKey;
+    if (!mod && !["Enter", "Tab", "Escape"].includes(e.key)) return;
+    const keys = [];
+    if (e.ctrlKey) keys.push("Ctrl");
+    if (e.altKey) keys.push("Alt");
+    if (e.shiftKey) keys.push("Shift");
+    if (e.metaKey) keys.push("Meta");
+    keys.push(names[e.key] || e.key);
+    push({type: "key", value: keys.join("+")});
+  }, true);
+
+  window.addEventListener("scroll", () => {
+    push({type: "scroll", x: Math.round(window.scrollX), y: Math.round(window.scrollY)});
+  }, true);
+};
+
+const fresh = globalThis.mnRecDoc !== document;
+if (fresh) {
+  globalThis.mnRecDoc = document;
+  install();
+}
+const events = store.load();
+store.save([]);
+return {fresh, url: location.href, start: performance.timeOrigin, events};
+`
+
+// Recorder captures clicks, typing, scrolls and navigations performed manually
+// in the browser
+//
+// It installs event listeners with ExecuteScript, and collects captured steps
+// when polling. Listeners are lost when navigating to another page, Poll detects
+// it and installs them again.
+//
+// Steps are kept in sessionStorage, so steps right before navigating to another
+// page in same origin are kept. Steps not collected before navigating to another
+// origin are lost. Poll frequently (Record polls every 200ms by default) to
+// minimize the loss.
+//
+// Typed text is recorded as-is, except for password fields (<input
+// type=password>), which are recorded as RecordedPassword. Replace it before
+// replaying. Key presses are recorded only for Enter, Tab, Escape and
+// combinations with Ctrl, Alt or Meta.
+type Recorder struct {
+	cl    *Commander
+	steps Recording
+	// time of last user event
+	last float64
+	// whether Poll has been called
+	started bool
+}
+
+// NewRecorder creates a Recorder recording current window of cl
+func NewRecorder(cl *Commander) (ret *Recorder) {
+	return &Recorder{cl: cl}
+}
+
+// Poll installs listeners if needed, and collects steps captured since last call
+//
+// The first call records a StepNavigate with current URL as starting point.
+func (r *Recorder) Poll() (err error) {
+	var res struct {
+		Fresh  bool           `json:"fresh"`
+		URL    string         `json:"url"`
+		Start  float64        `json:"start"`
+		Events []RecordedStep `json:"events"`
+	}
+	if err = r.cl.ExecuteScriptIn(recorderSandbox, jsRecorder, &res); err != nil {
+		return
+	}
+
+	for _, step := range res.Events {
+		r.steps = append(r.steps, step)
+		if step.Time > r.last {
+			r.last = step.Time
+		}
+	}
+
+	if !r.started || (res.Fresh && res.Start-r.last > recorderNavGap) {
+		r.steps = append(r.steps, RecordedStep{
+			Type:  StepNavigate,
+			Value: res.URL,
+			Time:  res.Start,
+		})
+	}
+	r.started = true
+	return
+}
+
+// isNavigating reports if err is caused by the page navigating or unloading
+//
+// Only error types are checked: ErrNoSuchFrame and ErrStaleElementReference.
+func isNavigating(err error) (ok bool) {
+	e, ok := err.(*marionette.ErrDriver)
+	if !ok {
+		return
+	}
+
+	switch e.Type {
+	case marionette.ErrNoSuchFrame, marionette.ErrStaleElementReference:
+		return true
+	}
+	return false
+}
+
+// Record polls every interval until ctx is done, and returns recorded steps
+//
+// Errors of type ErrNoSuchFrame or ErrStaleElementReference are treated as
+// caused by navigation and ignored, others are returned with steps recorded so
+// far. Error messages are not inspected, so a JavaScript error like "Document was
+// unloaded" is returned too; call Record again to continue recording, collected
+// steps are kept. Interval <= 0 means 200ms.
+func (r *Recorder) Record(ctx context.Context, interval time.Duration) (ret Recording, err error) {
+	if interval <= 0 {
+		interval = 200 * time.Millisecond
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err = r.Poll(); isNavigating(err) {
+			err = nil
+		}
+		if err != nil {
+			return r.Steps(), err
+		}
+
+		select {
+		case <-ctx.Done():
+			return r.Steps(), nil
+		case <-t.C:
+		}
+	}
+}
+
+// Steps returns a copy of steps collected so far
+func (r *Recorder) Steps() (ret Recording) {
+	return append(Recording(nil), r.steps...)
+}
+
+// Replay performs the steps with cl
+//
+// Before interacting with an element, it waits the element to be present (or
+// clickable for StepClick) until ctx is done. It stops at first failed step and
+// returns the error as-is.
+func (rec Recording) Replay(ctx context.Context, cl *Commander) (err error) {
+	find := func(step RecordedStep, cond Condition) (ret *marionette.WebElement, err error) {
+		if err = cl.Wait(ctx, cond, nil); err != nil {
+			return
+		}
+		return cl.FindElement(marionette.Selector, step.Selector, nil)
+	}
+
+	for _, step := range rec {
+		var el *marionette.WebElement
+		switch step.Type {
+		case StepNavigate:
+			err = cl.Navigate(step.Value)
+		case StepClick:
+			el, err = find(step, ElementClickable(marionette.Selector, step.Selector))
+			if err == nil {
+				err = cl.ElementClick(el)
+			}
+		case StepFill:
+			el, err = find(step, ElementPresent(marionette.Selector, step.Selector))
+			if err == nil {
+				err = cl.ElementClear(el)
+			}
+			if err == nil && step.Value != "" {
+				err = cl.ElementSendKeys(el, step.Value)
+			}
+		case StepSelect:
+			el, err = find(step, ElementPresent(marionette.Selector, step.Selector))
+			if err == nil {
+				err = cl.SelectByValue(el, step.Values...)
+			}
+		case StepKey:
+			var chain marionette.ActionChain
+			if _, err = chain.Shortcut(step.Value); err == nil {
+				err = cl.PerformActions(chain)
+			}
+		case StepScroll:
+			err = cl.ExecuteScript(
+				`window.scrollTo(arguments[0], arguments[1])`, nil, step.X, step.Y,
+			)
+		default:
+			err = errors.New("mnclient: unknown step type " + strconv.Quote(string(step.Type)))
+		}
+
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// GoSource generates a Go function named name, which performs the steps like
+// Replay using Commander API
+//
+// The generated function has signature
+//
+//	func name(ctx context.Context, cl *mnclient.Commander) (err error)
+//
+// and is formatted with gofmt.
+func (rec Recording) GoSource(name string) (ret string, err error) {
+	var (
+		body            bytes.Buffer
+		useEl, useChain bool
+	)
+	check := func(stmt string, args ...interface{}) {
+		fmt.Fprintf(&body, "if "+stmt+"; err != nil {\nreturn\n}\n", args...)
+	}
+	find := func(step RecordedStep, cond string) {
+		useEl = true
+		sel := strconv.Quote(step.Selector)
+		check("err = cl.Wait(ctx, mnclient.%s(marionette.Selector, %s), nil)", cond, sel)
+		check("el, err = cl.FindElement(marionette.Selector, %s, nil)", sel)
+	}
+
+	for _, step := range rec {
+		switch step.Type {
+		case StepNavigate:
+			check("err = cl.Navigate(%q)", step.Value)
+		case StepClick:
+			find(step, "ElementClickable")
+			check("err = cl.ElementClick(el)")
+		case StepFill:
+			find(step, "ElementPresent")
+			check("err = cl.ElementClear(el)")
+			if step.Value != "" {
+				check("err = cl.ElementSendKeys(el, %q)", step.Value)
+			}
+		case StepSelect:
+			find(step, "ElementPresent")
+			vals := make([]string, len(step.Values))
+			for i, v := range step.Values {
+				vals[i] = strconv.Quote(v)
+			}
+			check("err = cl.SelectByValue(el, %s)", strings.Join(vals, ", "))
+		case StepKey:
+			useChain = true
+			body.WriteString("chain = marionette.ActionChain{}\n")
+			check("_, err = chain.Shortcut(%q)", step.Value)
+			check("err = cl.PerformActions(chain)")
+		case StepScroll:
+			check("err = cl.ExecuteScript(`window.scrollTo(arguments[0], arguments[1])`, nil, %d, %d)", step.X, step.Y)
+		default:
+			return "", errors.New("mnclient: unknown step type " + strconv.Quote(string(step.Type)))
+		}
+		body.WriteString("\n")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "func %s(ctx context.Context, cl *mnclient.Commander) (err error) {\n", name)
+	if useEl {
+		buf.WriteString("var el *marionette.WebElement\n")
+	}
+	if useChain {
+		buf.WriteString("var chain marionette.ActionChain\n")
+	}
+	buf.WriteString("\n")
+	buf.Write(body.Bytes())
+	buf.WriteString("return\n}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return
+	}
+	return string(src), nil
+}
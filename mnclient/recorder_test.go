@@ -0,0 +1,194 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+func TestRecorderPoll(t *testing.T) {
+	polls := []map[string]interface{}{
+		{"fresh": true, "url": "http://a/", "start": 1000, "events": []interface{}{}},
+		{"fresh": false, "url": "http://a/", "start": 1000, "events": []interface{}{
+			map[string]interface{}{"type": "fill", "selector": "#q", "value": "go", "t": 2000},
+			map[string]interface{}{"type": "click", "selector": "#go", "t": 2100},
+		}},
+		// caused by the click
+		{"fresh": true, "url": "http://a/result", "start": 2500, "events": []interface{}{}},
+		// typed in url bar
+		{"fresh": true, "url": "http://b/", "start": 9000, "events": []interface{}{}},
+	}
+	idx := 0
+	cl := &Commander{Sender: &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		c, ok := cmd.(*mncmd.ExecuteScript)
+		if !ok || c.Script != jsRecorder || c.Sandbox != recorderSandbox {
+			t.Fatalf("unexpected command: %+v", cmd)
+		}
+		idx++
+		return fakeValue(polls[idx-1]), nil
+	}}}
+
+	r := NewRecorder(cl)
+	for range polls {
+		if err := r.Poll(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	expect := Recording{
+		{Type: StepNavigate, Value: "http://a/", Time: 1000},
+		{Type: StepFill, Selector: "#q", Value: "go", Time: 2000},
+		{Type: StepClick, Selector: "#go", Time: 2100},
+		{Type: StepNavigate, Value: "http://b/", Time: 9000},
+	}
+	if actual := r.Steps(); !reflect.DeepEqual(actual, expect) {
+		t.Fatalf("unexpected steps: %+v", actual)
+	}
+}
+
+var testRecording = Recording{
+	{Type: StepNavigate, Value: "http://a/"},
+	{Type: StepFill, Selector: `input[name="q"]`, Value: "go"},
+	{Type: StepKey, Value: "Enter"},
+	{Type: StepSelect, Selector: "#lang", Values: []string{"en", "fr"}},
+	// deselected all options
+	{Type: StepSelect, Selector: "#lang"},
+	{Type: StepScroll, X: 0, Y: 300},
+	{Type: StepClick, Selector: "#go"},
+}
+
+func TestRecordingReplay(t *testing.T) {
+	var selected []string
+	s := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		switch c := cmd.(type) {
+		case *mncmd.FindElement:
+			return fakeElem("elem"), nil
+		case *mncmd.ExecuteScript:
+			if c.Script == jsSelect {
+				buf, _ := json.Marshal(c.Args[2])
+				selected = append(selected, string(buf))
+				return fakeValue(""), nil
+			}
+			return fakeValue(nil), nil
+		case *mncmd.IsElementDisplayed, *mncmd.IsElementEnabled:
+			return fakeValue(true), nil
+		}
+		return nil, nil
+	}}
+	cl := &Commander{Sender: s}
+	if err := testRecording.Replay(context.Background(), cl); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cmds := strings.Join(s.commands(), ",")
+	for _, want := range []string{
+		"WebDriver:Navigate",
+		"WebDriver:ElementClear",
+		"WebDriver:ElementSendKeys",
+		"WebDriver:PerformActions",
+		"WebDriver:ElementClick",
+	} {
+		if !strings.Contains(cmds, want) {
+			t.Errorf("%s is not sent: %s", want, cmds)
+		}
+	}
+	if !reflect.DeepEqual(selected, []string{`["en","fr"]`, `[]`}) {
+		t.Errorf("unexpected selected options: %v", selected)
+	}
+}
+
+func TestRecorderRecordError(t *testing.T) {
+	var resp error
+	cl := &Commander{Sender: &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		return nil, resp
+	}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// navigating, ignored until ctx is done
+	resp = &marionette.ErrDriver{Type: marionette.ErrNoSuchFrame}
+	if _, err := NewRecorder(cl).Record(ctx, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// message is not inspected
+	resp = &marionette.ErrDriver{
+		Type:    marionette.ErrJavascriptError,
+		Message: "Document was unloaded",
+	}
+	if _, err := NewRecorder(cl).Record(context.Background(), time.Millisecond); err != resp {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp = &marionette.ErrDriver{
+		Type:    marionette.ErrJavascriptError,
+		Message: "SyntaxError: missing ) after argument list",
+	}
+	if _, err := NewRecorder(cl).Record(context.Background(), time.Millisecond); err != resp {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRecordingReplayUnknown(t *testing.T) {
+	cl := &Commander{Sender: &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		return nil, nil
+	}}}
+	rec := Recording{{Type: "hover", Selector: "#a"}}
+	if err := rec.Replay(context.Background(), cl); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRecordingGoSource(t *testing.T) {
+	src, err := testRecording.GoSource("login")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	file := "package x\n" + src
+	if _, err := parser.ParseFile(token.NewFileSet(), "x.go", file, 0); err != nil {
+		t.Fatalf("generated code is invalid: %s\n%s", err, src)
+	}
+	for _, want := range []string{
+		"func login(ctx context.Context, cl *mnclient.Commander) (err error) {",
+		"var el *marionette.WebElement",
+		"var chain marionette.ActionChain",
+		`cl.Navigate("http://a/")`,
+		`cl.FindElement(marionette.Selector, "input[name=\"q\"]", nil)`,
+		`cl.ElementSendKeys(el, "go")`,
+		`chain.Shortcut("Enter")`,
+		`cl.SelectByValue(el, "en", "fr")`,
+		"cl.SelectByValue(el);",
+		"nil, 0, 300)",
+		"mnclient.ElementClickable(marionette.Selector, \"#go\")",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("missing %s in generated code:\n%s", want, src)
+		}
+	}
+
+	// no unused variables
+	src, _ = Recording{{Type: StepNavigate, Value: "http://a/"}}.GoSource("f")
+	if strings.Contains(src, "var ") {
+		t.Fatalf("unexpected variables:\n%s", src)
+	}
+}
@@ -0,0 +1,122 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"errors"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// Session represents a webdriver session
+//
+// It owns the session id and negotiated capabilities. Commands are sent through
+// the embedded Commander.
+type Session struct {
+	ID           string
+	Capabilities *marionette.Capabilities
+	*Commander
+}
+
+// StartSession creates a new webdriver session
+//
+// Passing nil req creates a session with default options. See
+// Commander.NewSessionWithCaps for how capabilities are negotiated.
+func StartSession(cl *Commander, req *marionette.CapabilitiesRequest) (
+	ret *Session, err error,
+) {
+	if req == nil {
+		req = &marionette.CapabilitiesRequest{}
+	}
+	id, cap, err := cl.NewSessionWithCaps(req)
+	if err != nil {
+		return
+	}
+
+	ret = &Session{
+		ID:           id,
+		Capabilities: cap,
+		Commander:    cl,
+	}
+	return
+}
+
+// ErrAttachSession denotes the server created a new session instead of reusing
+// the desired one
+type ErrAttachSession struct {
+	ID     string
+	Actual string
+	// error deleting the new session, nil if deleted
+	Origin error
+}
+
+func (e *ErrAttachSession) Error() (ret string) {
+	ret = "mnclient: cannot attach to session " + e.ID + ", got " + e.Actual
+	if e.Origin != nil {
+		ret += ", and cannot delete it: " + e.Origin.Error()
+	}
+	return
+}
+
+// AttachSession binds cl to an existing session
+//
+// It asks marionette server to reuse the session id, so another process can take
+// over the browser through a new connection without restarting it. Marionette
+// accepts only one connection at the same time, the previous owner MUST
+// disconnect first.
+//
+// Pitfall
+//
+// Recent Firefox versions end the session when the connection is closed, so
+// attaching through a new connection works only if the server keeps the session.
+// Whether session states (like timeouts) are preserved also depends on Firefox
+// version. It returns an ErrAttachSession if the server creates a session with
+// different id, after deleting that session.
+func AttachSession(cl *Commander, id string) (ret *Session, err error) {
+	if id == "" {
+		return nil, errors.New("mnclient: empty session id")
+	}
+
+	cmd := &mncmd.NewSession{SessionID: id}
+	msg, err := cl.Sync(cmd)
+	if err != nil {
+		return
+	}
+	actual, cap, err := cmd.Decode(msg)
+	if err != nil {
+		return
+	}
+	if actual != id {
+		// do not leave the wrong session behind
+		return nil, &ErrAttachSession{
+			ID:     id,
+			Actual: actual,
+			Origin: cl.DeleteSession(),
+		}
+	}
+
+	ret = &Session{
+		ID:           id,
+		Capabilities: cap,
+		Commander:    cl,
+	}
+	return
+}
+
+// Delete ends the session
+//
+// The Session, including embedded Commander, SHOULD NOT be used to send commands
+// after deleted, except creating a new session.
+func (s *Session) Delete() (err error) {
+	return s.DeleteSession()
+}
@@ -0,0 +1,76 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"strings"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// sessionSender creates a new session with id, and fails DeleteSession with
+// delErr
+func sessionSender(id string, delErr error) (ret *fakeSender) {
+	return &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		switch cmd.(type) {
+		case *mncmd.NewSession:
+			return map[string]interface{}{
+				"sessionId":    id,
+				"capabilities": map[string]interface{}{"browserName": "firefox"},
+			}, nil
+		case *mncmd.DeleteSession:
+			return nil, delErr
+		}
+		return nil, &marionette.ErrDriver{Type: marionette.ErrUnknownCommand}
+	}}
+}
+
+func TestAttachSession(t *testing.T) {
+	s := sessionSender("abc", nil)
+	sess, err := AttachSession(&Commander{Sender: s}, "abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sess.ID != "abc" || sess.Capabilities == nil || sess.Capabilities.BrowserName != "firefox" {
+		t.Fatalf("unexpected session: %+v", sess)
+	}
+	if cmds := strings.Join(s.commands(), ","); cmds != "WebDriver:NewSession" {
+		t.Fatalf("unexpected commands: %s", cmds)
+	}
+	if id := s.sent[0].(*mncmd.NewSession).SessionID; id != "abc" {
+		t.Fatalf("unexpected requested session id: %s", id)
+	}
+}
+
+func TestAttachSessionMismatch(t *testing.T) {
+	s := sessionSender("new", nil)
+	sess, err := AttachSession(&Commander{Sender: s}, "abc")
+	if e, ok := err.(*ErrAttachSession); !ok || sess != nil || e.Actual != "new" || e.Origin != nil {
+		t.Fatalf("unexpected result: %+v, %v", sess, err)
+	}
+	cmds := strings.Join(s.commands(), ",")
+	if cmds != "WebDriver:NewSession,WebDriver:DeleteSession" {
+		t.Fatalf("new session is not deleted: %s", cmds)
+	}
+
+	// error of DeleteSession is reported along with the mismatch
+	e := &marionette.ErrDriver{Type: marionette.ErrInvalidSessionId}
+	_, err = AttachSession(&Commander{Sender: sessionSender("new", e)}, "abc")
+	if ae, ok := err.(*ErrAttachSession); !ok || ae.Origin != e {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "abc") || !strings.Contains(msg, e.Error()) {
+		t.Fatalf("unexpected message: %s", msg)
+	}
+}
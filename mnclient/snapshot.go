@@ -0,0 +1,148 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// SnapshotField denotes what to collect in Commander.Snapshot
+type SnapshotField string
+
+const (
+	// tag name in lower case
+	FieldTag SnapshotField = "tag"
+	// rendered text, like GetElementText
+	FieldText SnapshotField = "text"
+	// rect relative to document, like GetElementRect
+	FieldRect SnapshotField = "rect"
+	// rendered and not hidden by css, which is a heuristic rather than
+	// WebDriver's element displayedness check (IsElementDisplayed)
+	FieldDisplayed SnapshotField = "displayed"
+	FieldEnabled   SnapshotField = "enabled"
+	FieldSelected  SnapshotField = "selected"
+)
+
+// DefaultSnapshotFields are collected if no field is specified
+var DefaultSnapshotFields = []SnapshotField{
+	FieldTag, FieldText, FieldRect, FieldDisplayed, FieldEnabled, FieldSelected,
+}
+
+// FieldAttr collects an attribute
+func FieldAttr(name string) (ret SnapshotField) {
+	return SnapshotField("attr:" + name)
+}
+
+// FieldProp collects a property
+//
+// The property must be serializable by marionette.
+func FieldProp(name string) (ret SnapshotField) {
+	return SnapshotField("prop:" + name)
+}
+
+// FieldStyle collects a computed style
+func FieldStyle(name string) (ret SnapshotField) {
+	return SnapshotField("style:" + name)
+}
+
+// ElementSnapshot holds states of an element at the moment Snapshot is called
+//
+// Only requested fields are filled, others are left zero-valued. Attributes
+// which are not present on the element are not in Attrs.
+type ElementSnapshot struct {
+	Element   *marionette.WebElement `json:"-"`
+	Tag       string                 `json:"tag"`
+	Text      string                 `json:"text"`
+	Rect      marionette.Rect        `json:"rect"`
+	Displayed bool                   `json:"displayed"`
+	Enabled   bool                   `json:"enabled"`
+	Selected  bool                   `json:"selected"`
+	Attrs     map[string]string      `json:"attrs"`
+	Props     map[string]interface{} `json:"props"`
+	Styles    map[string]string      `json:"styles"`
+}
+
+const jsSnapshot = `
+const [els, fields] = arguments;
+` + jsVisible + `
+return els.map(el => {
+  const ret = {attrs: {}, props: {}, styles: {}};
+  let style;
+  for (const f of fields) {
+    const idx = f.indexOf(":");
+    const [kind, name] = idx < 0 ? [f, ""] : [f.substr(0, idx), f.substr(idx + 1)];
+    switch (kind) {
+    case "tag": ret.tag = el.tagName.toLowerCase(); break;
+    case "text": ret.text = el.innerText !== undefined ? el.innerText : el.textContent; break;
+    case "rect": {
+      const r = el.getBoundingClientRect();
+      ret.rect = {
+        x: r.left + window.pageXOffset, y: r.top + window.pageYOffset,
+        width: r.width, height: r.height,
+      };
+      break;
+    }
+    case "displayed": ret.displayed = visible(el); break;
+    case "enabled": ret.enabled = !el.matches(":disabled"); break;
+    case "selected": ret.selected = !!(el.checked || el.selected); break;
+    case "attr":
+      if (el.hasAttribute(name)) ret.attrs[name] = el.getAttribute(name);
+      break;
+    case "prop": ret.props[name] = el[name]; break;
+    case "style":
+      style = style || window.getComputedStyle(el);
+      ret.styles[name] = style.getPropertyValue(name);
+      break;
+    default: throw new Error("unknown snapshot field: " + f);
+    }
+  }
+  return ret;
+});
+`
+
+// Snapshot collects states of many elements in one ExecuteScript call
+//
+//	snaps, err := cl.Snapshot(els,
+//	    FieldTag, FieldText, FieldAttr("href"), FieldStyle("color"),
+//	)
+//
+// DefaultSnapshotFields is used if fields is empty. Result is in same order as
+// els.
+func (s *Commander) Snapshot(els []*marionette.WebElement, fields ...SnapshotField) (
+	ret []*ElementSnapshot, err error,
+) {
+	if len(els) == 0 {
+		return
+	}
+	if len(fields) == 0 {
+		fields = DefaultSnapshotFields
+	}
+
+	if err = s.ExecuteScript(jsSnapshot, &ret, els, fields); err != nil {
+		return
+	}
+	for idx, snap := range ret {
+		if idx < len(els) {
+			snap.Element = els[idx]
+		}
+	}
+	return
+}
+
+// Snapshot is identical to Commander.Snapshot, but collects only this element
+func (e *Element) Snapshot(fields ...SnapshotField) (ret *ElementSnapshot, err error) {
+	arr, err := e.Commander.Snapshot([]*marionette.WebElement{e.WebElement}, fields...)
+	if err == nil && len(arr) > 0 {
+		ret = arr[0]
+	}
+	return
+}
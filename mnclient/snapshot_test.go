@@ -0,0 +1,59 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"reflect"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+func TestSnapshot(t *testing.T) {
+	var args []interface{}
+	sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		args = cmd.(*mncmd.ExecuteScript).Args
+		return fakeValue([]map[string]interface{}{
+			{"tag": "a", "attrs": map[string]string{"href": "/"}},
+			{"tag": "b", "rect": map[string]float64{"x": 1, "width": 2}},
+		}), nil
+	}}
+	cl := &Commander{Sender: sender}
+
+	els := []*marionette.WebElement{
+		{Type: marionette.ElementType, UUID: "1"},
+		{Type: marionette.ElementType, UUID: "2"},
+	}
+	snaps, err := cl.Snapshot(els, FieldTag, FieldRect, FieldAttr("href"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if cmds := sender.commands(); len(cmds) != 1 {
+		t.Fatalf("expected exactly one call, got %v", cmds)
+	}
+	fields := []SnapshotField{FieldTag, FieldRect, "attr:href"}
+	if len(args) != 2 || !reflect.DeepEqual(args[1], fields) {
+		t.Fatalf("unexpected args: %+v", args)
+	}
+
+	if len(snaps) != 2 {
+		t.Fatalf("unexpected result: %+v", snaps)
+	}
+	if snaps[0].Element != els[0] || snaps[0].Tag != "a" || snaps[0].Attrs["href"] != "/" {
+		t.Errorf("unexpected snapshot#0: %+v", snaps[0])
+	}
+	if snaps[1].Element != els[1] || snaps[1].Rect != (marionette.Rect{X: 1, W: 2}) {
+		t.Errorf("unexpected snapshot#1: %+v", snaps[1])
+	}
+}
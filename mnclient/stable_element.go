@@ -0,0 +1,340 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"log"
+	"strconv"
+	"sync"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// StableElement is an Element which re-finds itself after page re-rendered
+//
+// It remembers how it was found (strategy, query string and the StableElement it
+// was found in). If a command fails with ErrStaleElementReference or
+// ErrNoSuchElement, it runs the lookup again (re-finding the root chain if
+// needed) and retries the command, at most MaxRetry times.
+//
+// It is useful for pages re-rendering DOM frequently, like SPA.
+type StableElement struct {
+	// max number of re-finding per command, 0 means 1
+	MaxRetry int
+	// logs every recovery, log.Printf is used if nil
+	Logf func(format string, v ...interface{})
+
+	by    marionette.FindStrategy
+	qstr  string
+	index int // -1 if found by FindElement
+	root  *StableElement
+	cl    *Commander
+
+	lock sync.Mutex
+	el   *Element
+}
+
+// FindStable is identical to Find, but returns StableElement
+func (s *Commander) FindStable(by marionette.FindStrategy, qstr string) (
+	ret *StableElement, err error,
+) {
+	ret = &StableElement{by: by, qstr: qstr, index: -1, cl: s}
+	if err = ret.refresh(); err != nil {
+		ret = nil
+	}
+	return
+}
+
+// FindAllStable is identical to FindAll, but returns StableElements
+//
+// Each element remembers its index in the result, and is re-found by the index.
+func (s *Commander) FindAllStable(by marionette.FindStrategy, qstr string) (
+	ret []*StableElement, err error,
+) {
+	els, err := s.FindAll(by, qstr)
+	if err != nil {
+		return
+	}
+
+	ret = make([]*StableElement, 0, len(els))
+	for idx, el := range els {
+		ret = append(ret, &StableElement{
+			by:    by,
+			qstr:  qstr,
+			index: idx,
+			cl:    s,
+			el:    el,
+		})
+	}
+	return
+}
+
+// isRecoverable checks if err means the element is not in the document (yet),
+// so finding it again might help
+func isRecoverable(err error) (ok bool) {
+	e, ok := err.(*marionette.ErrDriver)
+	if !ok {
+		return
+	}
+
+	return e.Type == marionette.ErrStaleElementReference ||
+		e.Type == marionette.ErrNoSuchElement
+}
+
+func (e *StableElement) logf(format string, v ...interface{}) {
+	f := e.Logf
+	if f == nil {
+		f = log.Printf
+	}
+	f(format, v...)
+}
+
+func (e *StableElement) String() (ret string) {
+	ret = string(e.by) + "=" + e.qstr
+	if e.index >= 0 {
+		ret += "[" + strconv.Itoa(e.index) + "]"
+	}
+	if e.root != nil {
+		ret = e.root.String() + " > " + ret
+	}
+	return
+}
+
+// Element returns currently resolved Element
+func (e *StableElement) Element() (ret *Element) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.el
+}
+
+func (e *StableElement) lookup(root *marionette.WebElement) (ret *Element, err error) {
+	if e.index < 0 {
+		var el *marionette.WebElement
+		if el, err = e.cl.FindElement(e.by, e.qstr, root); err == nil {
+			ret = NewElement(e.cl, el)
+		}
+		return
+	}
+
+	els, err := e.cl.FindElements(e.by, e.qstr, root)
+	if err != nil {
+		return
+	}
+	if e.index >= len(els) {
+		err = &marionette.ErrDriver{
+			Type:    marionette.ErrNoSuchElement,
+			Message: "no element at index " + strconv.Itoa(e.index),
+		}
+		return
+	}
+
+	return NewElement(e.cl, els[e.index]), nil
+}
+
+// refresh runs the lookup again, re-finding root chain if needed
+func (e *StableElement) refresh() (err error) {
+	var root *marionette.WebElement
+	if e.root != nil {
+		root = e.root.Element().WebElement
+	}
+
+	el, err := e.lookup(root)
+	if err != nil && e.root != nil && isRecoverable(err) {
+		if err = e.root.refresh(); err != nil {
+			return
+		}
+		el, err = e.lookup(e.root.Element().WebElement)
+	}
+	if err != nil {
+		return
+	}
+
+	e.lock.Lock()
+	e.el = el
+	e.lock.Unlock()
+	return
+}
+
+// Do runs f against resolved Element, re-finds and retries on stale reference
+func (e *StableElement) Do(f func(*Element) error) (err error) {
+	max := e.MaxRetry
+	if max < 1 {
+		max = 1
+	}
+
+	err = f(e.Element())
+	for x := 0; x < max && isRecoverable(err); x++ {
+		e.logf(
+			"mnclient: re-finding %s (%d/%d): %s",
+			e.String(), x+1, max, err,
+		)
+		if e2 := e.refresh(); e2 != nil {
+			return e2
+		}
+		err = f(e.Element())
+	}
+
+	return
+}
+
+// Find finds a StableElement inside this element
+func (e *StableElement) Find(by marionette.FindStrategy, qstr string) (
+	ret *StableElement, err error,
+) {
+	ret = &StableElement{
+		MaxRetry: e.MaxRetry,
+		Logf:     e.Logf,
+		by:       by,
+		qstr:     qstr,
+		index:    -1,
+		root:     e,
+		cl:       e.cl,
+	}
+	if err = ret.refresh(); err != nil {
+		ret = nil
+	}
+	return
+}
+
+// FindAll finds all matching StableElements inside this element
+func (e *StableElement) FindAll(by marionette.FindStrategy, qstr string) (
+	ret []*StableElement, err error,
+) {
+	var els []*Element
+	err = e.Do(func(el *Element) (err error) {
+		els, err = el.FindAll(by, qstr)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	ret = make([]*StableElement, 0, len(els))
+	for idx, el := range els {
+		ret = append(ret, &StableElement{
+			MaxRetry: e.MaxRetry,
+			Logf:     e.Logf,
+			by:       by,
+			qstr:     qstr,
+			index:    idx,
+			root:     e,
+			cl:       e.cl,
+			el:       el,
+		})
+	}
+	return
+}
+
+// Click clicks the element
+func (e *StableElement) Click() (err error) {
+	return e.Do(func(el *Element) error { return el.Click() })
+}
+
+// Clear clears the text of the element
+func (e *StableElement) Clear() (err error) {
+	return e.Do(func(el *Element) error { return el.Clear() })
+}
+
+// SendKeys sends keystrokes to the element
+func (e *StableElement) SendKeys(text string) (err error) {
+	return e.Do(func(el *Element) error { return el.SendKeys(text) })
+}
+
+// Text retrieves text of the element
+func (e *StableElement) Text() (ret string, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.Text()
+		return
+	})
+	return
+}
+
+// TagName retrieves tag name of the element
+func (e *StableElement) TagName() (ret string, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.TagName()
+		return
+	})
+	return
+}
+
+// Attr retrieves specified attribute of the element
+func (e *StableElement) Attr(key string) (ret string, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.Attr(key)
+		return
+	})
+	return
+}
+
+// Prop retrieves specified property of the element
+func (e *StableElement) Prop(key string) (ret interface{}, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.Prop(key)
+		return
+	})
+	return
+}
+
+// CSS retrieves specified css value of the element
+func (e *StableElement) CSS(key string) (ret string, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.CSS(key)
+		return
+	})
+	return
+}
+
+// Rect retrieves the bounding rect of the element
+func (e *StableElement) Rect() (ret marionette.Rect, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.Rect()
+		return
+	})
+	return
+}
+
+// IsDisplayed checks if the element is displayed
+func (e *StableElement) IsDisplayed() (ret bool, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.IsDisplayed()
+		return
+	})
+	return
+}
+
+// IsEnabled checks if the element is enabled
+func (e *StableElement) IsEnabled() (ret bool, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.IsEnabled()
+		return
+	})
+	return
+}
+
+// IsSelected checks if the element is selected
+func (e *StableElement) IsSelected() (ret bool, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		ret, err = el.IsSelected()
+		return
+	})
+	return
+}
+
+// Screenshot takes a screenshot of the element in base64-encoded png
+func (e *StableElement) Screenshot() (img string, err error) {
+	err = e.Do(func(el *Element) (err error) {
+		img, err = el.Screenshot()
+		return
+	})
+	return
+}
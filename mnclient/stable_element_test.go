@@ -0,0 +1,110 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"strconv"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// a page which re-renders (changes uuid of every element) on demand
+type rerenderPage struct {
+	gen int
+}
+
+func (p *rerenderPage) uuid(name string) (ret string) {
+	return name + "#" + strconv.Itoa(p.gen)
+}
+
+func (p *rerenderPage) handle(cmd mncmd.Command) (data interface{}, err error) {
+	stale := &marionette.ErrDriver{Type: marionette.ErrStaleElementReference}
+	switch c := cmd.(type) {
+	case *mncmd.FindElement:
+		if c.RootElement != nil && c.RootElement.UUID != p.uuid("form") {
+			return nil, stale
+		}
+		return fakeElem(p.uuid(c.Value)), nil
+	case *mncmd.GetElementText:
+		if c.Element.UUID != p.uuid("button") {
+			return nil, stale
+		}
+		return fakeValue("ok"), nil
+	}
+
+	return nil, &marionette.ErrDriver{Type: marionette.ErrUnknownCommand}
+}
+
+func TestStableElement(t *testing.T) {
+	page := &rerenderPage{}
+	sender := &fakeSender{handler: page.handle}
+	cl := &Commander{Sender: sender}
+
+	form, err := cl.FindStable(marionette.Selector, "form")
+	if err != nil {
+		t.Fatalf("cannot find form: %s", err)
+	}
+	logs := 0
+	form.Logf = func(string, ...interface{}) { logs++ }
+
+	btn, err := form.Find(marionette.Selector, "button")
+	if err != nil {
+		t.Fatalf("cannot find button: %s", err)
+	}
+
+	t.Run("no-rerender", func(t *testing.T) {
+		if txt, err := btn.Text(); err != nil || txt != "ok" {
+			t.Fatalf("unexpected result: %s, %v", txt, err)
+		}
+		if logs != 0 {
+			t.Fatalf("unexpected recovery: %d", logs)
+		}
+	})
+
+	t.Run("rerender", func(t *testing.T) {
+		page.gen++
+		txt, err := btn.Text()
+		if err != nil || txt != "ok" {
+			t.Fatalf("unexpected result: %s, %v", txt, err)
+		}
+		if logs != 1 {
+			t.Fatalf("expected 1 recovery, got %d", logs)
+		}
+		if uuid := btn.Element().UUID; uuid != page.uuid("button") {
+			t.Fatalf("unexpected uuid: %s", uuid)
+		}
+		if uuid := form.Element().UUID; uuid != page.uuid("form") {
+			t.Fatalf("root is not re-found: %s", uuid)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		sender.handler = func(cmd mncmd.Command) (interface{}, error) {
+			if _, ok := cmd.(*mncmd.FindElement); ok {
+				return page.handle(cmd)
+			}
+			return nil, &marionette.ErrDriver{Type: marionette.ErrStaleElementReference}
+		}
+		btn.MaxRetry = 3
+		logs = 0
+		_, err := btn.Text()
+		e, ok := err.(*marionette.ErrDriver)
+		if !ok || e.Type != marionette.ErrStaleElementReference {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if logs != 3 {
+			t.Fatalf("expected 3 recoveries, got %d", logs)
+		}
+	})
+}
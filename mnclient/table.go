@@ -0,0 +1,213 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+const jsTableGrid = `
+const table = arguments[0];
+const rows = Array.from(table.rows);
+const grid = rows.map(() => []);
+rows.forEach((tr, r) => {
+  let c = 0;
+  for (const cell of tr.cells) {
+    while (grid[r][c] !== undefined) c++;
+    const text = (cell.innerText !== undefined ? cell.innerText : cell.textContent).trim();
+    const rs = cell.rowSpan === 0 ? rows.length - r : Math.max(cell.rowSpan, 1);
+    const cs = Math.max(cell.colSpan, 1);
+    for (let i = 0; i < rs && r + i < rows.length; i++) {
+      for (let j = 0; j < cs; j++) {
+        grid[r + i][c + j] = text;
+      }
+    }
+    c += cs;
+  }
+});
+return grid.map(row => Array.from(row, x => x === undefined ? "" : x));
+`
+
+// TableRows extracts text of every cell in the <table> element
+//
+// Rows in thead, tbody and tfoot are all included in document order. Cells with
+// colspan/rowspan are expanded, so every spanned position has the same text. Short
+// rows are padded with empty string to the length of longest column they reach.
+//
+// It costs only one ExecuteScript call.
+func (s *Commander) TableRows(table *marionette.WebElement) (ret [][]string, err error) {
+	err = s.ExecuteScript(jsTableGrid, &ret, table)
+	return
+}
+
+// TableMaps extracts rows of the <table> element as header => text mappings
+//
+// First row is used as header. If there are duplicated header names, latter one
+// wins.
+func (s *Commander) TableMaps(table *marionette.WebElement) (ret []map[string]string, err error) {
+	grid, err := s.TableRows(table)
+	if err != nil || len(grid) == 0 {
+		return
+	}
+
+	header := grid[0]
+	ret = make([]map[string]string, 0, len(grid)-1)
+	for _, row := range grid[1:] {
+		m := make(map[string]string, len(header))
+		for idx, name := range header {
+			if idx < len(row) {
+				m[name] = row[idx]
+			}
+		}
+		ret = append(ret, m)
+	}
+	return
+}
+
+// TableUnmarshal extracts rows of the <table> element into slice of structs
+//
+// First row is used as header, and dst must be a pointer to slice of struct (or
+// pointer to struct). Fields are mapped to columns by "table" tag, which can be a
+// header name or a 0-based column index prefixed with "#":
+//
+//	type Row struct {
+//	    Name  string  `table:"Name"`
+//	    Price float64 `table:"Unit Price"`
+//	    Code  int     `table:"#0"`
+//	    Note  string  // no tag, skipped
+//	}
+//
+//	var rows []Row
+//	err := cl.TableUnmarshal(tableElement, &rows)
+//
+// Supported field types are string, bool, ints, uints and floats. Empty cells
+// leave the field zero-valued.
+func (s *Commander) TableUnmarshal(table *marionette.WebElement, dst interface{}) (err error) {
+	grid, err := s.TableRows(table)
+	if err != nil {
+		return
+	}
+
+	return unmarshalTable(grid, dst)
+}
+
+func unmarshalTable(grid [][]string, dst interface{}) (err error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return errors.New("mnclient: dst must be pointer to slice")
+	}
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return errors.New("mnclient: dst must be pointer to slice of struct")
+	}
+
+	var header []string
+	if len(grid) > 0 {
+		header = grid[0]
+	}
+
+	// field index => column index
+	cols := map[int]int{}
+	for i := 0; i < structType.NumField(); i++ {
+		sf := structType.Field(i)
+		tag := sf.Tag.Get("table")
+		if tag == "" || sf.PkgPath != "" {
+			continue
+		}
+		col := -1
+		if strings.HasPrefix(tag, "#") {
+			if col, err = strconv.Atoi(tag[1:]); err != nil {
+				return errors.New("mnclient: invalid column index " + tag)
+			}
+		} else {
+			for idx, name := range header {
+				if name == tag {
+					col = idx
+				}
+			}
+		}
+		if col < 0 {
+			return errors.New("mnclient: column " + tag + " not found")
+		}
+		cols[i] = col
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(grid))
+	for r := 1; r < len(grid); r++ {
+		item := reflect.New(structType).Elem()
+		for fidx, col := range cols {
+			if col >= len(grid[r]) {
+				continue
+			}
+			if err = setField(item.Field(fidx), grid[r][col]); err != nil {
+				return errors.New(
+					"mnclient: cannot set " + structType.Field(fidx).Name +
+						" of row#" + strconv.Itoa(r) + ": " + err.Error(),
+				)
+			}
+		}
+		if isPtr {
+			item = item.Addr()
+		}
+		result = reflect.Append(result, item)
+	}
+	slice.Set(result)
+
+	return
+}
+
+// setField converts str to the type of f
+func setField(f reflect.Value, str string) (err error) {
+	if str == "" {
+		return
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(str)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(str); err == nil {
+			f.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(strings.Replace(str, ",", "", -1), 10, f.Type().Bits()); err == nil {
+			f.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var i uint64
+		if i, err = strconv.ParseUint(strings.Replace(str, ",", "", -1), 10, f.Type().Bits()); err == nil {
+			f.SetUint(i)
+		}
+	case reflect.Float32, reflect.Float64:
+		var x float64
+		if x, err = strconv.ParseFloat(strings.Replace(str, ",", "", -1), f.Type().Bits()); err == nil {
+			f.SetFloat(x)
+		}
+	default:
+		err = errors.New("unsupported type " + f.Type().String())
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import "testing"
+
+func TestUnmarshalTable(t *testing.T) {
+	grid := [][]string{
+		{"Name", "Price", "Qty", "OK"},
+		{"a", "1.5", "1,000", "true"},
+		{"b", "", "2"},
+	}
+
+	type row struct {
+		Name  string  `table:"Name"`
+		Price float64 `table:"Price"`
+		Qty   uint    `table:"#2"`
+		OK    bool    `table:"OK"`
+		Note  string
+	}
+
+	var rows []*row
+	if err := unmarshalTable(grid, &rows); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if x := *rows[0]; x != (row{"a", 1.5, 1000, true, ""}) {
+		t.Errorf("unexpected row#0: %+v", x)
+	}
+	if x := *rows[1]; x != (row{"b", 0, 2, false, ""}) {
+		t.Errorf("unexpected row#1: %+v", x)
+	}
+
+	t.Run("MissingColumn", func(t *testing.T) {
+		var rows []struct {
+			X string `table:"X"`
+		}
+		if err := unmarshalTable(grid, &rows); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+	t.Run("BadValue", func(t *testing.T) {
+		var rows []struct {
+			X int `table:"Name"`
+		}
+		if err := unmarshalTable(grid, &rows); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+	t.Run("Overflow", func(t *testing.T) {
+		var rows []struct {
+			Qty int8 `table:"Qty"`
+		}
+		if err := unmarshalTable(grid, &rows); err == nil {
+			t.Fatal("expected error when 1,000 overflows int8")
+		}
+		var urows []struct {
+			Qty uint8 `table:"Qty"`
+		}
+		if err := unmarshalTable(grid, &urows); err == nil {
+			t.Fatal("expected error when 1,000 overflows uint8")
+		}
+	})
+	t.Run("Unexported", func(t *testing.T) {
+		var rows []struct {
+			Name string `table:"Name"`
+			qty  int    `table:"Qty"`
+		}
+		if err := unmarshalTable(grid, &rows); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(rows) != 2 || rows[0].Name != "a" || rows[0].qty != 0 {
+			t.Fatalf("unexpected rows: %+v", rows)
+		}
+	})
+	t.Run("NotSlice", func(t *testing.T) {
+		var x struct{}
+		if err := unmarshalTable(grid, &x); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,286 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// umField is compiled form of a struct field with "mn" tag
+type umField struct {
+	By      marionette.FindStrategy `json:"by,omitempty"`
+	Query   string                  `json:"query,omitempty"`
+	Extract string                  `json:"extract"`
+	Arg     string                  `json:"arg,omitempty"`
+	Multi   bool                    `json:"multi"`
+	Sub     []*umField              `json:"sub,omitempty"`
+
+	name     string
+	index    int
+	optional bool
+}
+
+var typeWebElement = reflect.TypeOf(&marionette.WebElement{})
+
+// parseTag parses the "mn" tag, see Unmarshal for syntax
+func parseTag(tag string) (ret *umField, err error) {
+	ret = &umField{}
+	var sel []string
+	for idx, part := range strings.Split(tag, ",") {
+		key, arg := part, ""
+		if x := strings.Index(part, "="); x >= 0 {
+			key, arg = part[:x], part[x+1:]
+		}
+
+		switch {
+		case idx == 0 && key == "css":
+			ret.By = marionette.Selector
+			sel = append(sel, arg)
+		case idx == 0 && key == "xpath":
+			ret.By = marionette.XPath
+			sel = append(sel, arg)
+		case key == "text" || key == "html" || key == "outerhtml" || key == "elem":
+			ret.Extract = key
+		case key == "attr" || key == "prop":
+			ret.Extract, ret.Arg = key, arg
+		case key == "optional":
+			ret.optional = true
+		case len(sel) > 0 && ret.Extract == "" && !ret.optional:
+			// comma inside selector, like "css=h1, h2"
+			sel = append(sel, part)
+		default:
+			return nil, errors.New("mnclient: invalid tag " + strconv.Quote(tag))
+		}
+	}
+	ret.Query = strings.Join(sel, ",")
+	return
+}
+
+// compileStruct compiles tagged fields of t
+func compileStruct(t reflect.Type) (ret []*umField, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("mn")
+		if !ok || tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		f, err := parseTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		f.name = sf.Name
+		f.index = i
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8 {
+			f.Multi = true
+			ft = ft.Elem()
+		}
+		if ft == typeWebElement {
+			f.Extract = "elem"
+		} else if f.Extract == "elem" {
+			return nil, errors.New(
+				"mnclient: field " + sf.Name + " must be *marionette.WebElement to use elem",
+			)
+		} else if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && f.Extract == "" {
+			f.Extract = "sub"
+			if f.Sub, err = compileStruct(ft); err != nil {
+				return nil, err
+			}
+		}
+		if f.Extract == "" {
+			f.Extract = "text"
+		}
+		ret = append(ret, f)
+	}
+	return
+}
+
+const jsUnmarshal = `
+const [spec, root] = arguments;
+const query = (f, scope) => {
+  if (!f.query) return [scope];
+  if (f.by !== "xpath") return Array.from(scope.querySelectorAll(f.query));
+  const r = document.evaluate(f.query, scope, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+  const ret = [];
+  for (let i = 0; i < r.snapshotLength; i++) ret.push(r.snapshotItem(i));
+  return ret;
+};
+const extract = (f, el) => {
+  switch (f.extract) {
+  case "text": return (el.innerText !== undefined ? el.innerText : el.textContent).trim();
+  case "html": return el.innerHTML;
+  case "outerhtml": return el.outerHTML;
+  case "attr": return el.getAttribute(f.arg);
+  case "prop": return el[f.arg];
+  case "elem": return el;
+  case "sub": return fill(f.sub || [], el);
+  }
+  throw new Error("unknown extractor: " + f.extract);
+};
+const fill = (fields, scope) => fields.map(f => {
+  const els = query(f, scope);
+  return (f.multi ? els : els.slice(0, 1)).map(el => extract(f, el));
+});
+return fill(spec, root || document.documentElement);
+`
+
+// Unmarshal fills dst with contents of the DOM, inside root if not nil
+//
+// dst must be a pointer to struct. Fields are filled according to the "mn" tag,
+// which is a comma-separated list:
+//
+//   - "css=selector" or "xpath=expr" (must be first) finds element relative to
+//     the root; the root itself is used if omitted
+//   - "text" (default), "html", "outerhtml", "attr=name", "prop=name" or "elem"
+//     denotes what to extract from the element
+//   - "optional" leaves the field untouched if no element is found, or an
+//     error is returned
+//
+// Slice fields collect every matching element. Struct (or pointer to struct)
+// fields are filled recursively using found element as root, so repeated blocks
+// can be described as slice of struct:
+//
+//	type Item struct {
+//	    Name  string   `mn:"css=.name"`
+//	    Price float64  `mn:"css=.price,text"`
+//	    Link  string   `mn:"css=a,attr=href"`
+//	    Tags  []string `mn:"css=.tag"`
+//	    ID    int      `mn:"attr=data-id"`
+//	}
+//	type Page struct {
+//	    Title string `mn:"css=h1"`
+//	    Items []Item `mn:"css=.item"`
+//	    Next  *marionette.WebElement `mn:"css=a.next,optional"`
+//	}
+//
+// Extracted strings are converted with the same rules as TableUnmarshal.
+// Non-string properties are converted via JSON.
+//
+// It costs only one ExecuteScript call.
+func Unmarshal(cl *Commander, root *marionette.WebElement, dst interface{}) (err error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("mnclient: dst must be pointer to struct")
+	}
+	spec, err := compileStruct(v.Elem().Type())
+	if err != nil {
+		return
+	}
+
+	var vals []interface{}
+	if err = cl.ExecuteScript(jsUnmarshal, &vals, spec, root); err != nil {
+		return
+	}
+
+	return fillStruct(spec, vals, v.Elem(), v.Elem().Type().Name())
+}
+
+// Unmarshal is identical to Unmarshal(e.Commander, e.WebElement, dst)
+func (e *Element) Unmarshal(dst interface{}) (err error) {
+	return Unmarshal(e.Commander, e.WebElement, dst)
+}
+
+func fillStruct(spec []*umField, vals []interface{}, v reflect.Value, path string) (err error) {
+	if len(vals) != len(spec) {
+		return errors.New("mnclient: unexpected result for " + path)
+	}
+
+	for idx, f := range spec {
+		fpath := path + "." + f.name
+		arr, _ := vals[idx].([]interface{})
+		fv := v.Field(f.index)
+
+		if f.Multi {
+			slice := reflect.MakeSlice(fv.Type(), len(arr), len(arr))
+			for i, x := range arr {
+				p := fpath + "[" + strconv.Itoa(i) + "]"
+				if err = fillValue(f, x, slice.Index(i), p); err != nil {
+					return
+				}
+			}
+			fv.Set(slice)
+			continue
+		}
+
+		if len(arr) == 0 {
+			if f.optional {
+				continue
+			}
+			return &marionette.ErrDriver{
+				Type:    marionette.ErrNoSuchElement,
+				Message: "no element for " + fpath,
+			}
+		}
+		if err = fillValue(f, arr[0], fv, fpath); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func fillValue(f *umField, val interface{}, v reflect.Value, path string) (err error) {
+	if f.Extract == "elem" {
+		if el, ok := val.(*marionette.WebElement); ok {
+			v.Set(reflect.ValueOf(el))
+		}
+		return
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if val == nil {
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		if err = fillValue(f, val, p.Elem(), path); err == nil {
+			v.Set(p)
+		}
+		return
+	}
+
+	switch x := val.(type) {
+	case nil:
+	case []interface{}:
+		if f.Extract == "sub" {
+			return fillStruct(f.Sub, x, v, path)
+		}
+		err = fillJSON(x, v)
+	case string:
+		err = setField(v, x)
+	default:
+		err = fillJSON(x, v)
+	}
+
+	if err != nil {
+		err = errors.New("mnclient: cannot set " + path + ": " + err.Error())
+	}
+	return
+}
+
+func fillJSON(val interface{}, v reflect.Value) (err error) {
+	buf, err := json.Marshal(val)
+	if err == nil {
+		err = json.Unmarshal(buf, v.Addr().Interface())
+	}
+	return
+}
@@ -0,0 +1,138 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"reflect"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		tag    string
+		expect umField
+	}{
+		{"css=.price", umField{By: marionette.Selector, Query: ".price"}},
+		{"css=.price,text", umField{By: marionette.Selector, Query: ".price", Extract: "text"}},
+		{"css=a,attr=href", umField{By: marionette.Selector, Query: "a", Extract: "attr", Arg: "href"}},
+		{"css=h1, h2,html", umField{By: marionette.Selector, Query: "h1, h2", Extract: "html"}},
+		{"xpath=//a[@x=1],optional", umField{By: marionette.XPath, Query: "//a[@x=1]", optional: true}},
+		{"attr=data-id", umField{Extract: "attr", Arg: "data-id"}},
+	}
+
+	for _, c := range cases {
+		f, err := parseTag(c.tag)
+		if err != nil {
+			t.Errorf("cannot parse %s: %s", c.tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(*f, c.expect) {
+			t.Errorf("unexpected result of %s: %+v", c.tag, *f)
+		}
+	}
+
+	for _, tag := range []string{"text,css=a", "unknown", "css=a,text,whatever"} {
+		if _, err := parseTag(tag); err == nil {
+			t.Errorf("expected error for %s", tag)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	type item struct {
+		Name  string   `mn:"css=.name"`
+		Price float64  `mn:"css=.price"`
+		Tags  []string `mn:"css=.tag"`
+		ID    *int     `mn:"attr=data-id"`
+	}
+	type page struct {
+		Title string                 `mn:"css=h1"`
+		Items []item                 `mn:"css=.item"`
+		Next  *marionette.WebElement `mn:"css=a.next,optional"`
+		Count int                    `mn:"css=.items,prop=childElementCount"`
+		Skip  string
+	}
+
+	sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		return fakeValue([]interface{}{
+			[]interface{}{"Title"},
+			[]interface{}{
+				[]interface{}{
+					[]interface{}{"a"}, []interface{}{"1,000.5"},
+					[]interface{}{"x", "y"}, []interface{}{"3"},
+				},
+				[]interface{}{
+					[]interface{}{"b"}, []interface{}{""},
+					[]interface{}{}, []interface{}{nil},
+				},
+			},
+			[]interface{}{},
+			[]interface{}{2},
+		}), nil
+	}}
+	cl := &Commander{Sender: sender}
+
+	var p page
+	if err := Unmarshal(cl, nil, &p); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	three := 3
+	expected := page{
+		Title: "Title",
+		Items: []item{
+			{Name: "a", Price: 1000.5, Tags: []string{"x", "y"}, ID: &three},
+			{Name: "b", Tags: []string{}},
+		},
+		Count: 2,
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Fatalf("unexpected result: %+v", p)
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		var x struct {
+			Title string `mn:"css=h1"`
+		}
+		sender.handler = func(cmd mncmd.Command) (interface{}, error) {
+			return fakeValue([]interface{}{[]interface{}{}}), nil
+		}
+		err := Unmarshal(cl, nil, &x)
+		if e, ok := err.(*marionette.ErrDriver); !ok || e.Type != marionette.ErrNoSuchElement {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestUnmarshalElemType(t *testing.T) {
+	cl := &Commander{Sender: &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		t.Fatalf("unexpected command: %s", cmd.Command())
+		return nil, nil
+	}}}
+
+	var dst struct {
+		Link string `mn:"css=a,elem"`
+	}
+	if err := Unmarshal(cl, nil, &dst); err == nil {
+		t.Fatal("expected error for elem on string field")
+	}
+
+	var ok struct {
+		Links []*marionette.WebElement `mn:"css=a,elem"`
+	}
+	if _, err := compileStruct(reflect.TypeOf(ok)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,224 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// Condition checks if something you're waiting for is ready
+//
+// The state is a human-readable description of what it observed, which will be
+// reported in ErrWaitTimeout. Returning non-nil err aborts the waiting.
+//
+// Conditions in this package treat ErrNoSuchElement and ErrStaleElementReference
+// as "not ready yet" instead of errors.
+type Condition func(cl *Commander) (ok bool, state string, err error)
+
+// WaitOptions represents options of Commander.Wait
+type WaitOptions struct {
+	// time between two checks, default to 100ms
+	Interval time.Duration
+	// 0 means waiting until ctx is done
+	Timeout time.Duration
+}
+
+// ErrWaitTimeout denotes the condition is not met before timeout
+type ErrWaitTimeout struct {
+	// state observed in last check
+	LastState string
+	// error from context, context.DeadlineExceeded or context.Canceled
+	Err error
+}
+
+func (e *ErrWaitTimeout) Error() (ret string) {
+	return "mnclient: condition not met (" + e.Err.Error() + "), last state: " + e.LastState
+}
+
+// Wait checks cond periodically until it is met, ctx is done or error occurred
+//
+// Passing nil opts uses default options. The condition is checked immediately,
+// and then once per opts.Interval.
+func (s *Commander) Wait(ctx context.Context, cond Condition, opts *WaitOptions) (err error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	state := "not checked"
+	for {
+		select {
+		case <-ctx.Done():
+			return &ErrWaitTimeout{LastState: state, Err: ctx.Err()}
+		case <-timer.C:
+		}
+
+		var ok bool
+		ok, state, err = cond(s)
+		if err != nil || ok {
+			return
+		}
+		timer.Reset(interval)
+	}
+}
+
+// elementCondition finds the element and runs f against it
+func elementCondition(
+	by marionette.FindStrategy, qstr string,
+	f func(*Commander, *marionette.WebElement) (bool, string, error),
+) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		el, err := cl.FindElement(by, qstr, nil)
+		if err == nil {
+			ok, state, err = f(cl, el)
+		}
+		if isRecoverable(err) {
+			return false, "element " + qstr + " not found", nil
+		}
+		return
+	}
+}
+
+// Predicate creates a Condition from a simple function
+func Predicate(f func(*Commander) (bool, error)) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		ok, err = f(cl)
+		return ok, "predicate returns " + strconv.FormatBool(ok), err
+	}
+}
+
+// ScriptTrue checks if the script returns true
+func ScriptTrue(script string, args ...interface{}) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		var v interface{}
+		if err = cl.ExecuteScript(script, &v, args...); err != nil {
+			return
+		}
+		ok, _ = v.(bool)
+		data, _ := json.Marshal(v)
+		return ok, "script returns " + string(data), nil
+	}
+}
+
+// ElementPresent checks if the element exists in the document
+func ElementPresent(by marionette.FindStrategy, qstr string) (ret Condition) {
+	return elementCondition(by, qstr, func(*Commander, *marionette.WebElement) (bool, string, error) {
+		return true, "element " + qstr + " found", nil
+	})
+}
+
+// ElementVisible checks if the element is displayed
+func ElementVisible(by marionette.FindStrategy, qstr string) (ret Condition) {
+	return elementCondition(by, qstr, func(cl *Commander, el *marionette.WebElement) (
+		ok bool, state string, err error,
+	) {
+		ok, err = cl.IsElementDisplayed(el)
+		return ok, "element " + qstr + " displayed: " + strconv.FormatBool(ok), err
+	})
+}
+
+// ElementClickable checks if the element is displayed and enabled
+func ElementClickable(by marionette.FindStrategy, qstr string) (ret Condition) {
+	return elementCondition(by, qstr, func(cl *Commander, el *marionette.WebElement) (
+		ok bool, state string, err error,
+	) {
+		shown, err := cl.IsElementDisplayed(el)
+		if err != nil {
+			return
+		}
+		enabled, err := cl.IsElementEnabled(el)
+		if err != nil {
+			return
+		}
+		state = "element " + qstr +
+			" displayed: " + strconv.FormatBool(shown) +
+			", enabled: " + strconv.FormatBool(enabled)
+		return shown && enabled, state, nil
+	})
+}
+
+// TextContains checks if text of the element contains specified string
+func TextContains(by marionette.FindStrategy, qstr, text string) (ret Condition) {
+	return elementCondition(by, qstr, func(cl *Commander, el *marionette.WebElement) (
+		ok bool, state string, err error,
+	) {
+		actual, err := cl.GetElementText(el)
+		return strings.Contains(actual, text), "text of " + qstr + ": " + strconv.Quote(actual), err
+	})
+}
+
+// AttributeEquals checks if specified attribute of the element equals to value
+func AttributeEquals(by marionette.FindStrategy, qstr, name, value string) (ret Condition) {
+	return elementCondition(by, qstr, func(cl *Commander, el *marionette.WebElement) (
+		ok bool, state string, err error,
+	) {
+		actual, err := cl.GetElementAttribute(el, name)
+		state = "attribute " + name + " of " + qstr + ": " + strconv.Quote(actual)
+		return actual == value, state, err
+	})
+}
+
+// ElementCount checks if number of matching elements equals to n
+func ElementCount(by marionette.FindStrategy, qstr string, n int) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		els, err := cl.FindElements(by, qstr, nil)
+		if err != nil {
+			return
+		}
+		l := len(els)
+		return l == n, strconv.Itoa(l) + " elements match " + qstr, nil
+	}
+}
+
+// Staleness checks if the element is removed from the document
+func Staleness(el *marionette.WebElement) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		_, err = cl.GetElementTagName(el)
+		if isRecoverable(err) {
+			return true, "element is stale", nil
+		}
+		return false, "element is still attached", err
+	}
+}
+
+// URLMatches checks if current url matches the regexp
+func URLMatches(re *regexp.Regexp) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		uri, err := cl.GetCurrentURL()
+		return re.MatchString(uri), "url: " + uri, err
+	}
+}
+
+// TitleMatches checks if title of current document matches the regexp
+func TitleMatches(re *regexp.Regexp) (ret Condition) {
+	return func(cl *Commander) (ok bool, state string, err error) {
+		title, err := cl.GetTitle()
+		return re.MatchString(title), "title: " + strconv.Quote(title), err
+	}
+}
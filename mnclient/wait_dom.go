@@ -0,0 +1,265 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// DOMCondition is a condition evaluated inside the page
+//
+// Check is body of a js function, which receives an array "args" and returns true
+// when ready. It is called immediately and whenever DOM mutates (or every frame
+// if EveryFrame is set), so it should be fast and side-effect free.
+type DOMCondition struct {
+	Check string
+	Args  []interface{}
+	// watch with requestAnimationFrame instead of MutationObserver, useful if
+	// the state you want changes without DOM mutation (like css animation)
+	EveryFrame bool
+	// optional script executed once before waiting, the result is appended to
+	// Args (useful to record initial state)
+	Init string
+	// human-readable description, used in ErrWaitTimeout
+	Desc string
+}
+
+// SelectorAppears waits until an element matching the css selector exists
+func SelectorAppears(sel string) (ret *DOMCondition) {
+	return &DOMCondition{
+		Check: `return document.querySelector(args[0]) !== null;`,
+		Args:  []interface{}{sel},
+		Desc:  sel + " appears",
+	}
+}
+
+// SelectorDisappears waits until no element matches the css selector
+func SelectorDisappears(sel string) (ret *DOMCondition) {
+	return &DOMCondition{
+		Check: `return document.querySelector(args[0]) === null;`,
+		Args:  []interface{}{sel},
+		Desc:  sel + " disappears",
+	}
+}
+
+// defines js function "visible", which checks if the element is rendered and not
+// hidden by css
+//
+// It is a heuristic, which differs from WebDriver's element displayedness
+// algorithm (IsElementDisplayed) in details like opacity and overflow.
+const jsVisible = `
+const visible = el => {
+  if (el.getClientRects().length === 0) return false;
+  const s = window.getComputedStyle(el);
+  return s.visibility !== "hidden" && s.display !== "none";
+};
+`
+
+// SelectorVisible waits until first element matching the css selector is visible
+//
+// Visibility is checked every frame, an element is visible if it is rendered and
+// is not hidden by css visibility or display.
+func SelectorVisible(sel string) (ret *DOMCondition) {
+	return &DOMCondition{
+		Check: jsVisible + `
+const el = document.querySelector(args[0]);
+return el !== null && visible(el);
+`,
+		Args:       []interface{}{sel},
+		EveryFrame: true,
+		Desc:       sel + " is visible",
+	}
+}
+
+// TextChanges waits until text of the element differs from when waiting started
+//
+// It also resolves if the element is removed or added.
+func TextChanges(sel string) (ret *DOMCondition) {
+	return &DOMCondition{
+		Init: `
+const el = document.querySelector(arguments[0]);
+return el === null ? null : el.textContent;
+`,
+		Check: `
+const el = document.querySelector(args[0]);
+return (el === null ? null : el.textContent) !== args[1];
+`,
+		Args: []interface{}{sel},
+		Desc: "text of " + sel + " changes",
+	}
+}
+
+const jsWatchDOM = `
+const args = arguments[0];
+const ms = arguments[1];
+const resolve = arguments[arguments.length - 1];
+const check = function(args) {
+%s
+};
+const everyFrame = %t;
+let done = false, obs = null, raf = 0, timer = 0;
+const finish = v => {
+  if (done) return;
+  done = true;
+  if (obs !== null) obs.disconnect();
+  if (raf) cancelAnimationFrame(raf);
+  clearTimeout(timer);
+  resolve(v);
+};
+const test = () => {
+  try {
+    if (check(args)) finish(true);
+  } catch (e) {
+    finish({error: String(e)});
+  }
+};
+timer = setTimeout(() => finish(false), ms);
+test();
+if (!done && everyFrame) {
+  const loop = () => {
+    test();
+    if (!done) raf = requestAnimationFrame(loop);
+  };
+  raf = requestAnimationFrame(loop);
+} else if (!done) {
+  obs = new MutationObserver(test);
+  obs.observe(document, {
+    childList: true, subtree: true, attributes: true, characterData: true,
+  });
+}
+`
+
+// max duration of a single watching script, must be shorter than script timeout
+const domWatchSlice = 5 * time.Second
+
+// per-document token, changes when the page navigates
+const jsDocToken = `return String(performance.timeOrigin);`
+
+// isInterrupted reports if err is caused by script timeout or navigation,
+// token is updated if document is changed
+func (s *Commander) isInterrupted(err error, token *string) (ok bool) {
+	e, ok := err.(*marionette.ErrDriver)
+	if !ok {
+		return
+	}
+
+	switch e.Type {
+	case marionette.ErrScriptTimeout:
+		return true
+	case marionette.ErrJavascriptError:
+		var cur string
+		if s.ExecuteScript(jsDocToken, &cur) != nil || cur == *token {
+			return false
+		}
+		*token = cur
+		return true
+	}
+	return false
+}
+
+// WaitDOM waits until cond is met, by watching the page instead of polling
+//
+// It injects a MutationObserver (or requestAnimationFrame loop) through
+// ExecuteAsyncScript, which resolves as soon as the condition is met. Navigation
+// destroys the watcher, in such case WaitDOM re-injects it after opts.Interval,
+// which works like polling. Each watcher lives at most 5 seconds, so script timeout
+// of the session should be longer than that.
+//
+// Javascript errors not caused by navigation, including exceptions thrown by
+// cond.Check, are returned immediately.
+//
+// Passing nil opts uses default options, see Wait.
+func (s *Commander) WaitDOM(ctx context.Context, cond *DOMCondition, opts *WaitOptions) (err error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	args := cond.Args
+	if args == nil {
+		args = []interface{}{}
+	}
+	if cond.Init != "" {
+		var v interface{}
+		if err = s.ExecuteScript(cond.Init, &v, args...); err != nil {
+			return
+		}
+		args = append(append([]interface{}{}, args...), v)
+	}
+
+	var token string
+	if err = s.ExecuteScript(jsDocToken, &token); err != nil {
+		return
+	}
+
+	script := fmt.Sprintf(jsWatchDOM, cond.Check, cond.EveryFrame)
+	state := cond.Desc + ": not checked"
+	for {
+		slice := domWatchSlice
+		if dl, ok := ctx.Deadline(); ok {
+			if d := time.Until(dl); d < slice {
+				slice = d
+			}
+		}
+		if slice <= 0 {
+			return &ErrWaitTimeout{LastState: state, Err: context.DeadlineExceeded}
+		}
+
+		ch, e := s.ExecuteAsyncScript(script, args, int(slice/time.Millisecond))
+		if e != nil {
+			return e
+		}
+
+		var res ScriptResult
+		select {
+		case <-ctx.Done():
+			return &ErrWaitTimeout{LastState: state, Err: ctx.Err()}
+		case res = <-ch:
+		}
+
+		if res.Err == nil {
+			if m, ok := res.Result.(map[string]interface{}); ok {
+				return &marionette.ErrDriver{
+					Type:    marionette.ErrJavascriptError,
+					Message: fmt.Sprint(m["error"]),
+				}
+			}
+			if res.Result == true {
+				return nil
+			}
+			state = cond.Desc + ": not met"
+			continue
+		}
+		if !s.isInterrupted(res.Err, &token) {
+			return res.Err
+		}
+		state = cond.Desc + ": watcher interrupted: " + res.Err.Error()
+
+		select {
+		case <-ctx.Done():
+			return &ErrWaitTimeout{LastState: state, Err: ctx.Err()}
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+func TestWaitDOM(t *testing.T) {
+	// 1st watcher is interrupted by navigation, 2nd is sliced, 3rd resolves
+	cnt := 0
+	var scripts []*mncmd.ExecuteAsyncScript
+	sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		switch c := cmd.(type) {
+		case *mncmd.ExecuteScript:
+			if c.Script == jsDocToken {
+				return fakeValue(strconv.Itoa(cnt)), nil
+			}
+			return fakeValue("initial"), nil
+		case *mncmd.ExecuteAsyncScript:
+			scripts = append(scripts, c)
+			cnt++
+			switch cnt {
+			case 1:
+				return nil, &marionette.ErrDriver{
+					Type:    marionette.ErrJavascriptError,
+					Message: "Document was unloaded",
+				}
+			case 2:
+				return fakeValue(false), nil
+			}
+			return fakeValue(true), nil
+		}
+		return nil, errors.New("unexpected command")
+	}}
+	cl := &Commander{Sender: sender}
+
+	err := cl.WaitDOM(
+		context.Background(),
+		TextChanges("#result"),
+		&WaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cnt != 3 {
+		t.Fatalf("expected 3 watchers, got %d", cnt)
+	}
+
+	s := scripts[0]
+	if !strings.Contains(s.Script, "new MutationObserver") ||
+		!strings.Contains(s.Script, "args[1]") {
+		t.Errorf("unexpected script: %s", s.Script)
+	}
+	args, ok := s.Args[0].([]interface{})
+	if !ok || len(args) != 2 || args[0] != "#result" || args[1] != "initial" {
+		t.Errorf("unexpected args: %#v", s.Args)
+	}
+	if ms, _ := s.Args[1].(int); ms <= 0 || ms > 1000 {
+		t.Errorf("unexpected watching time: %#v", s.Args[1])
+	}
+}
+
+func TestWaitDOMTimeout(t *testing.T) {
+	sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		if _, ok := cmd.(*mncmd.ExecuteScript); ok {
+			return fakeValue("token"), nil
+		}
+		return fakeValue(false), nil
+	}}
+	cl := &Commander{Sender: sender}
+
+	err := cl.WaitDOM(
+		context.Background(),
+		SelectorAppears("#nothing"),
+		&WaitOptions{Timeout: 20 * time.Millisecond},
+	)
+	e, ok := err.(*ErrWaitTimeout)
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(e.LastState, "#nothing appears") {
+		t.Errorf("unexpected last state: %s", e.LastState)
+	}
+}
+
+func TestWaitDOMScriptError(t *testing.T) {
+	cases := map[string]interface{}{
+		// exception thrown by Check
+		"throws": fakeValue(map[string]interface{}{"error": "TypeError: x is null"}),
+		// syntax error, document is not changed
+		"syntax": &marionette.ErrDriver{
+			Type:    marionette.ErrJavascriptError,
+			Message: "SyntaxError: unexpected token",
+		},
+	}
+	for name, resp := range cases {
+		t.Run(name, func(t *testing.T) {
+			cnt := 0
+			sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+				switch cmd.(type) {
+				case *mncmd.ExecuteScript:
+					return fakeValue("token"), nil
+				case *mncmd.ExecuteAsyncScript:
+					cnt++
+					if err, ok := resp.(error); ok {
+						return nil, err
+					}
+					return resp, nil
+				}
+				return nil, errors.New("unexpected command")
+			}}
+			cl := &Commander{Sender: sender}
+
+			err := cl.WaitDOM(
+				context.Background(),
+				&DOMCondition{Check: "return x.y;"},
+				&WaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+			)
+			e, ok := err.(*marionette.ErrDriver)
+			if !ok || e.Type != marionette.ErrJavascriptError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cnt != 1 {
+				t.Fatalf("expected 1 watcher, got %d", cnt)
+			}
+		})
+	}
+}
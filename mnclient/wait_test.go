@@ -0,0 +1,154 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mnclient
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+func TestWait(t *testing.T) {
+	// the element appears at 3rd check, text changes at 5th check
+	cnt := 0
+	sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		switch cmd.(type) {
+		case *mncmd.FindElement:
+			cnt++
+			if cnt < 3 {
+				return nil, &marionette.ErrDriver{Type: marionette.ErrNoSuchElement}
+			}
+			return fakeElem("result"), nil
+		case *mncmd.GetElementText:
+			if cnt < 5 {
+				return fakeValue("loading"), nil
+			}
+			return fakeValue("done"), nil
+		case *mncmd.GetTitle:
+			return fakeValue("My Page"), nil
+		}
+		return nil, errors.New("unexpected command")
+	}}
+	cl := &Commander{Sender: sender}
+	opts := &WaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+
+	t.Run("TextContains", func(t *testing.T) {
+		err := cl.Wait(
+			context.Background(),
+			TextContains(marionette.ID, "result", "done"),
+			opts,
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if cnt != 5 {
+			t.Fatalf("expected 5 checks, got %d", cnt)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		err := cl.Wait(
+			context.Background(),
+			TitleMatches(regexp.MustCompile(`^Other`)),
+			&WaitOptions{Interval: time.Millisecond, Timeout: 20 * time.Millisecond},
+		)
+		e, ok := err.(*ErrWaitTimeout)
+		if !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e.Err != context.DeadlineExceeded {
+			t.Errorf("unexpected context error: %s", e.Err)
+		}
+		if !strings.Contains(e.LastState, "My Page") {
+			t.Errorf("unexpected last state: %s", e.LastState)
+		}
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		my := errors.New("my error")
+		err := cl.Wait(
+			context.Background(),
+			Predicate(func(*Commander) (bool, error) { return false, my }),
+			opts,
+		)
+		if err != my {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestConditions(t *testing.T) {
+	// #shown is displayed and enabled, #hidden is neither, #gone is missing
+	sender := &fakeSender{handler: func(cmd mncmd.Command) (interface{}, error) {
+		missing := &marionette.ErrDriver{Type: marionette.ErrNoSuchElement}
+		switch c := cmd.(type) {
+		case *mncmd.FindElement:
+			if c.Value == "gone" {
+				return nil, missing
+			}
+			return fakeElem(c.Value), nil
+		case *mncmd.FindElements:
+			return []interface{}{
+				map[string]string{marionette.ElementType: "a"},
+				map[string]string{marionette.ElementType: "b"},
+			}, nil
+		case *mncmd.IsElementDisplayed:
+			return fakeValue(c.Element.UUID == "shown"), nil
+		case *mncmd.IsElementEnabled:
+			return fakeValue(c.Element.UUID == "shown"), nil
+		case *mncmd.GetElementAttribute:
+			return fakeValue(c.Element.UUID + "-" + c.Name), nil
+		case *mncmd.GetElementTagName:
+			if c.Element.UUID == "stale" {
+				return nil, &marionette.ErrDriver{Type: marionette.ErrStaleElementReference}
+			}
+			return fakeValue("div"), nil
+		}
+		return nil, errors.New("unexpected command")
+	}}
+	cl := &Commander{Sender: sender}
+
+	cases := []struct {
+		name  string
+		cond  Condition
+		ok    bool
+		state string
+	}{
+		{"Visible", ElementVisible(marionette.ID, "shown"), true, "displayed: true"},
+		{"Invisible", ElementVisible(marionette.ID, "hidden"), false, "displayed: false"},
+		{"VisibleMissing", ElementVisible(marionette.ID, "gone"), false, "not found"},
+		{"Clickable", ElementClickable(marionette.ID, "shown"), true, "enabled: true"},
+		{"NotClickable", ElementClickable(marionette.ID, "hidden"), false, "enabled: false"},
+		{"AttributeEquals", AttributeEquals(marionette.ID, "shown", "id", "shown-id"), true, `"shown-id"`},
+		{"AttributeDiffers", AttributeEquals(marionette.ID, "shown", "id", "x"), false, `"shown-id"`},
+		{"ElementCount", ElementCount(marionette.Selector, "li", 2), true, "2 elements"},
+		{"ElementCountDiffers", ElementCount(marionette.Selector, "li", 3), false, "2 elements"},
+		{"Stale", Staleness(&marionette.WebElement{UUID: "stale"}), true, "stale"},
+		{"Attached", Staleness(&marionette.WebElement{UUID: "x"}), false, "attached"},
+	}
+	for _, c := range cases {
+		ok, state, err := c.cond(cl)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+			continue
+		}
+		if ok != c.ok || !strings.Contains(state, c.state) {
+			t.Errorf("%s: unexpected result: %v, %s", c.name, ok, state)
+		}
+	}
+}
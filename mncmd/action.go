@@ -30,7 +30,7 @@ func (c *PerformActions) Param() (ret interface{}) {
 }
 
 func (c *PerformActions) Validate() (ok bool) {
-	return len(c.Actions) > 0
+	return len(c.Actions.Sequences) > 0 && c.Actions.Err() == nil
 }
 
 // ReleaseActions defines "WebDriver:ReleaseActions" command
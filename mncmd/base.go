@@ -81,6 +81,13 @@ func (p parameter) SetP(key string, data interface{}) {
 	}
 }
 
+// SetE sets UUID of el
+func (p parameter) SetE(key string, el *marionette.WebElement) {
+	if el != nil {
+		p[key] = el.UUID
+	}
+}
+
 // mixin for commands which needs no parameter
 type noParam struct{}
 
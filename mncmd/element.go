@@ -62,8 +62,8 @@ func (c *ElementClick) Validate() (ok bool) {
 // See GeckoDriver.prototype.sendKeysToElement
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2504
 type ElementSendKeys struct {
-	Element *marionette.WebElement `json:"id"`
-	Text    string                 `json:"text"`
+	Element *marionette.WebElement
+	Text    string
 }
 
 func (c *ElementSendKeys) Command() (ret string) {
@@ -71,7 +71,10 @@ func (c *ElementSendKeys) Command() (ret string) {
 }
 
 func (c *ElementSendKeys) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id":   c.Element.UUID,
+		"text": c.Text,
+	}
 }
 
 func (c *ElementSendKeys) Validate() (ok bool) {
@@ -83,9 +86,9 @@ func (c *ElementSendKeys) Validate() (ok bool) {
 // See GeckoDriver.prototype.findElement
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L1974
 type FindElement struct {
-	Using       marionette.FindStrategy `json:"using"`
-	Value       string                  `json:"value"`
-	RootElement *marionette.WebElement  `json:"element,omitempty"`
+	Using       marionette.FindStrategy
+	Value       string
+	RootElement *marionette.WebElement
 	returnElem
 }
 
@@ -94,7 +97,13 @@ func (c *FindElement) Command() (ret string) {
 }
 
 func (c *FindElement) Param() (ret interface{}) {
-	return c
+	x := parameter{
+		"using": c.Using,
+		"value": c.Value,
+	}
+	x.SetE("element", c.RootElement)
+
+	return x
 }
 
 func (c *FindElement) Validate() (ok bool) {
@@ -106,9 +115,9 @@ func (c *FindElement) Validate() (ok bool) {
 // See GeckoDriver.prototype.findElements
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2024
 type FindElements struct {
-	Using       marionette.FindStrategy `json:"using"`
-	Value       string                  `json:"value"`
-	RootElement *marionette.WebElement  `json:"element,omitempty"`
+	Using       marionette.FindStrategy
+	Value       string
+	RootElement *marionette.WebElement
 	returnElems
 }
 
@@ -117,7 +126,13 @@ func (c *FindElements) Command() (ret string) {
 }
 
 func (c *FindElements) Param() (ret interface{}) {
-	return c
+	x := parameter{
+		"using": c.Using,
+		"value": c.Value,
+	}
+	x.SetE("element", c.RootElement)
+
+	return x
 }
 
 func (c *FindElements) Validate() (ok bool) {
@@ -142,8 +157,8 @@ func (c *GetActiveElement) Command() (ret string) {
 // See GeckoDriver.prototype.getElementAttribute
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2148
 type GetElementAttribute struct {
-	Element *marionette.WebElement `json:"id"`
-	Name    string                 `json:"name"`
+	Element *marionette.WebElement
+	Name    string
 	returnStr
 }
 
@@ -152,7 +167,10 @@ func (c *GetElementAttribute) Command() (ret string) {
 }
 
 func (c *GetElementAttribute) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id":   c.Element.UUID,
+		"name": c.Name,
+	}
 }
 
 func (c *GetElementAttribute) Validate() (ok bool) {
@@ -164,8 +182,8 @@ func (c *GetElementAttribute) Validate() (ok bool) {
 // See GeckoDriver.prototype.getElementCSSValue
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2348
 type GetElementCSSValue struct {
-	Element *marionette.WebElement `json:"id"`
-	Prop    string                 `json:"propertyName"`
+	Element *marionette.WebElement
+	Prop    string
 	returnStr
 }
 
@@ -174,7 +192,10 @@ func (c *GetElementCSSValue) Command() (ret string) {
 }
 
 func (c *GetElementCSSValue) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id":           c.Element.UUID,
+		"propertyName": c.Prop,
+	}
 }
 
 func (c *GetElementCSSValue) Validate() (ok bool) {
@@ -186,8 +207,8 @@ func (c *GetElementCSSValue) Validate() (ok bool) {
 // See GeckoDriver.prototype.getElementProperty
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2189
 type GetElementProperty struct {
-	Element *marionette.WebElement `json:"id"`
-	Name    string                 `json:"name"`
+	Element *marionette.WebElement
+	Name    string
 	returnMixed
 }
 
@@ -196,7 +217,10 @@ func (c *GetElementProperty) Command() (ret string) {
 }
 
 func (c *GetElementProperty) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id":   c.Element.UUID,
+		"name": c.Name,
+	}
 }
 
 func (c *GetElementProperty) Validate() (ok bool) {
@@ -208,7 +232,7 @@ func (c *GetElementProperty) Validate() (ok bool) {
 // See GeckoDriver.prototype.getElementRect
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2468
 type GetElementRect struct {
-	Element *marionette.WebElement `json:"id"`
+	Element *marionette.WebElement
 	returnRect
 }
 
@@ -217,7 +241,9 @@ func (c *GetElementRect) Command() (ret string) {
 }
 
 func (c *GetElementRect) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id": c.Element.UUID,
+	}
 }
 
 func (c *GetElementRect) Validate() (ok bool) {
@@ -229,7 +255,7 @@ func (c *GetElementRect) Validate() (ok bool) {
 // See GeckoDriver.prototype.getElementTagName
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2272
 type GetElementTagName struct {
-	Element *marionette.WebElement `json:"id"`
+	Element *marionette.WebElement
 	returnStr
 }
 
@@ -238,7 +264,9 @@ func (c *GetElementTagName) Command() (ret string) {
 }
 
 func (c *GetElementTagName) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id": c.Element.UUID,
+	}
 }
 
 func (c *GetElementTagName) Validate() (ok bool) {
@@ -250,7 +278,7 @@ func (c *GetElementTagName) Validate() (ok bool) {
 // See GeckoDriver.prototype.getElementText
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2230
 type GetElementText struct {
-	Element *marionette.WebElement `json:"id"`
+	Element *marionette.WebElement
 	returnStr
 }
 
@@ -259,7 +287,9 @@ func (c *GetElementText) Command() (ret string) {
 }
 
 func (c *GetElementText) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id": c.Element.UUID,
+	}
 }
 
 func (c *GetElementText) Validate() (ok bool) {
@@ -271,7 +301,7 @@ func (c *GetElementText) Validate() (ok bool) {
 // See GeckoDriver.prototype.isElementDisplayed
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2310
 type IsElementDisplayed struct {
-	Element *marionette.WebElement `json:"id"`
+	Element *marionette.WebElement
 	returnBool
 }
 
@@ -280,7 +310,9 @@ func (c *IsElementDisplayed) Command() (ret string) {
 }
 
 func (c *IsElementDisplayed) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id": c.Element.UUID,
+	}
 }
 
 func (c *IsElementDisplayed) Validate() (ok bool) {
@@ -292,7 +324,7 @@ func (c *IsElementDisplayed) Validate() (ok bool) {
 // See GeckoDriver.prototype.isElementEnabled
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2390
 type IsElementEnabled struct {
-	Element *marionette.WebElement `json:"id"`
+	Element *marionette.WebElement
 	returnBool
 }
 
@@ -301,7 +333,9 @@ func (c *IsElementEnabled) Command() (ret string) {
 }
 
 func (c *IsElementEnabled) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id": c.Element.UUID,
+	}
 }
 
 func (c *IsElementEnabled) Validate() (ok bool) {
@@ -313,7 +347,7 @@ func (c *IsElementEnabled) Validate() (ok bool) {
 // See GeckoDriver.prototype.isElementSelected
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L2429
 type IsElementSelected struct {
-	Element *marionette.WebElement `json:"id"`
+	Element *marionette.WebElement
 	returnBool
 }
 
@@ -322,7 +356,9 @@ func (c *IsElementSelected) Command() (ret string) {
 }
 
 func (c *IsElementSelected) Param() (ret interface{}) {
-	return c
+	return map[string]interface{}{
+		"id": c.Element.UUID,
+	}
 }
 
 func (c *IsElementSelected) Validate() (ok bool) {
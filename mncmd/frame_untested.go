@@ -31,9 +31,9 @@ func (c *GetActiveFrame) Command() (ret string) {
 // See GeckoDriver.prototype.switchToFrame
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L1656
 type SwitchToFrame struct {
-	Element *marionette.WebElement `json:"element,omitempty"`
-	ID      interface{}            `json:"id,omitempty"` // must be int/uint/string
-	Focus   bool                   `json:"focus,omitempty"`
+	Element *marionette.WebElement
+	ID      interface{} // must be int/uint/string
+	Focus   bool
 }
 
 func (c *SwitchToFrame) Command() (ret string) {
@@ -41,7 +41,14 @@ func (c *SwitchToFrame) Command() (ret string) {
 }
 
 func (c *SwitchToFrame) Param() (ret interface{}) {
-	return c
+	x := parameter{}
+	x.SetE("element", c.Element)
+	if c.ID != nil {
+		x["id"] = c.ID
+	}
+	x.SetB("focus", c.Focus)
+
+	return x
 }
 
 func (c *SwitchToFrame) Validate() (ok bool) {
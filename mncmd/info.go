@@ -12,11 +12,17 @@
 package mncmd
 
 import (
+	"encoding/json"
+
 	marionette "github.com/raohwork/marionette-go"
 )
 
 // NewSession represents "WebDriver:NewSession" command
 //
+// Capabilities, if set, is sent as base of other fields. Marionette does not
+// process alwaysMatch/firstMatch itself, see marionette.CapabilitiesRequest for
+// merging them.
+//
 // See GeckoDriver.prototype.newSession
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L587
 type NewSession struct {
@@ -28,6 +34,7 @@ type NewSession struct {
 	SpecialPointerOrigin bool
 	WebdriverClick       bool
 	SessionID            string
+	Capabilities         *marionette.Capabilities
 }
 
 func (c *NewSession) Decode(msg *marionette.Message) (
@@ -56,6 +63,10 @@ func (c *NewSession) Command() (ret string) {
 
 func (c *NewSession) Param() (data interface{}) {
 	cap := parameter(map[string]interface{}{})
+	if c.Capabilities != nil {
+		buf, _ := json.Marshal(c.Capabilities)
+		_ = json.Unmarshal(buf, &cap)
+	}
 
 	if c.SessionID != "" {
 		cap.SetS("sessionId", c.SessionID)
@@ -77,7 +88,19 @@ func (c *NewSession) Param() (data interface{}) {
 }
 
 func (c *NewSession) Validate() (ok bool) {
-	return true
+	return c.Capabilities == nil || c.Capabilities.Validate() == nil
+}
+
+// DeleteSession defines "WebDriver:DeleteSession" command
+//
+// See GeckoDriver.prototype.deleteSession
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type DeleteSession struct {
+	noParam
+}
+
+func (c *DeleteSession) Command() (ret string) {
+	return "WebDriver:DeleteSession"
 }
 
 // SetTimeouts defines "WebDriver:SetTimeouts" command
@@ -153,3 +176,37 @@ func (c *GetCapabilities) Decode(msg *marionette.Message) (ret *marionette.Capab
 func (c *GetCapabilities) Command() (ret string) {
 	return "WebDriver:GetCapabilities"
 }
+
+// SetPermission defines "WebDriver:SetPermission" command
+//
+// Descriptor must contain at least "name", like {"name": "geolocation"}. The
+// permission is applied to the origin of current document.
+//
+// See GeckoDriver.prototype.setPermission
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type SetPermission struct {
+	Descriptor map[string]interface{} `json:"descriptor"`
+	State      string                 `json:"state"`
+	OneRealm   bool                   `json:"oneRealm,omitempty"`
+}
+
+func (c *SetPermission) Command() (ret string) {
+	return "WebDriver:SetPermission"
+}
+
+func (c *SetPermission) Param() (ret interface{}) {
+	return c
+}
+
+func (c *SetPermission) Validate() (ok bool) {
+	if c.Descriptor == nil {
+		return
+	}
+	if name, _ := c.Descriptor["name"].(string); name == "" {
+		return
+	}
+
+	return c.State == marionette.PermissionGranted ||
+		c.State == marionette.PermissionDenied ||
+		c.State == marionette.PermissionPrompt
+}
@@ -16,7 +16,8 @@ import marionette "github.com/raohwork/marionette-go"
 // ExecuteScript defines "WebDriver:ExecuteScript" command
 //
 // Unlike other commands, the Decode() method of ExecuteScript accepts dest, which
-// works as go idiom, json.Unmarshal().
+// works as go idiom, json.Unmarshal(). Web element references in dest are
+// resolved to *marionette.WebElement, see marionette.ResolveElements.
 //
 // See GeckoDriver.prototype.executeScript
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L859
@@ -39,7 +40,10 @@ func (c *ExecuteScript) Decode(msg *marionette.Message, dest interface{}) (err e
 	if dest != nil {
 		resp.Value = dest
 	}
-	return recode(msg, &resp)
+	if err = recode(msg, &resp); err == nil {
+		marionette.ResolveElements(dest)
+	}
+	return
 }
 
 func (c *ExecuteScript) Command() (ret string) {
@@ -69,7 +73,8 @@ func (c *ExecuteScript) Validate() (ok bool) {
 // ExecuteAsyncScript defines "WebDriver:ExecuteAsyncScript" command
 //
 // Unlike other commands, the Decode() method of ExecuteAsyncScript accepts dest,
-// which works as go idiom, json.Unmarshal().
+// which works as go idiom, json.Unmarshal(). Web element references in dest are
+// resolved like ExecuteScript.
 //
 // See GeckoDriver.prototype.executeAsyncScript
 // https://github.com/mozilla/gecko-dev/blob/master/testing/marionette/driver.js#L914
@@ -90,7 +95,10 @@ func (c *ExecuteAsyncScript) Decode(msg *marionette.Message, dest interface{}) (
 
 	var resp nonObjResp
 	resp.Value = dest
-	return recode(msg, &resp)
+	if err = recode(msg, &resp); err == nil {
+		marionette.ResolveElements(dest)
+	}
+	return
 }
 
 func (c *ExecuteAsyncScript) Command() (ret string) {
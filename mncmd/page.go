@@ -127,9 +127,13 @@ func (c *TakeScreenshot) Command() (ret string) {
 
 func (c *TakeScreenshot) Param() (ret interface{}) {
 	x := parameter{}
-	x.SetP("id", c.Element)
+	x.SetE("id", c.Element)
 	if len(c.Highlights) > 0 {
-		x.SetP("highlights", c.Highlights)
+		ids := make([]string, 0, len(c.Highlights))
+		for _, el := range c.Highlights {
+			ids = append(ids, el.UUID)
+		}
+		x["highlights"] = ids
 	}
 	x.SetNotB("full", c.ViewportOnly)
 	x.SetB("hash", c.Hash)
@@ -0,0 +1,188 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package mncmd
+
+import (
+	"encoding/json"
+
+	marionette "github.com/raohwork/marionette-go"
+)
+
+// AddVirtualAuthenticator defines "WebAuthn:AddVirtualAuthenticator" command
+//
+// Decode() returns the id of created authenticator.
+//
+// See GeckoDriver.prototype.addVirtualAuthenticator
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type AddVirtualAuthenticator struct {
+	Options *marionette.VirtualAuthenticator
+	returnStr
+}
+
+func (c *AddVirtualAuthenticator) Command() (ret string) {
+	return "WebAuthn:AddVirtualAuthenticator"
+}
+
+func (c *AddVirtualAuthenticator) Param() (ret interface{}) {
+	return c.Options
+}
+
+func (c *AddVirtualAuthenticator) Validate() (ok bool) {
+	return c.Options != nil &&
+		c.Options.Protocol != "" &&
+		c.Options.Transport != ""
+}
+
+// RemoveVirtualAuthenticator defines "WebAuthn:RemoveVirtualAuthenticator" command
+//
+// See GeckoDriver.prototype.removeVirtualAuthenticator
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type RemoveVirtualAuthenticator struct {
+	ID string `json:"authenticatorId"`
+}
+
+func (c *RemoveVirtualAuthenticator) Command() (ret string) {
+	return "WebAuthn:RemoveVirtualAuthenticator"
+}
+
+func (c *RemoveVirtualAuthenticator) Param() (ret interface{}) {
+	return c
+}
+
+func (c *RemoveVirtualAuthenticator) Validate() (ok bool) {
+	return c.ID != ""
+}
+
+// AddCredential defines "WebAuthn:AddCredential" command
+//
+// See GeckoDriver.prototype.addCredential
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type AddCredential struct {
+	ID         string
+	Credential *marionette.Credential
+}
+
+func (c *AddCredential) Command() (ret string) {
+	return "WebAuthn:AddCredential"
+}
+
+func (c *AddCredential) Param() (ret interface{}) {
+	// flatten credential fields into parameter
+	x := parameter{}
+	buf, _ := json.Marshal(c.Credential)
+	_ = json.Unmarshal(buf, &x)
+	x["authenticatorId"] = c.ID
+
+	return x
+}
+
+func (c *AddCredential) Validate() (ok bool) {
+	return c.ID != "" &&
+		c.Credential != nil &&
+		len(c.Credential.ID) > 0 &&
+		c.Credential.RpID != "" &&
+		len(c.Credential.PrivateKey) > 0
+}
+
+// GetCredentials defines "WebAuthn:GetCredentials" command
+//
+// See GeckoDriver.prototype.getCredentials
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type GetCredentials struct {
+	ID string `json:"authenticatorId"`
+}
+
+func (c *GetCredentials) Decode(msg *marionette.Message) (ret []*marionette.Credential, err error) {
+	if msg.Error != nil {
+		err = msg.Error
+		return
+	}
+
+	err = recode(msg, &ret)
+	return
+}
+
+func (c *GetCredentials) Command() (ret string) {
+	return "WebAuthn:GetCredentials"
+}
+
+func (c *GetCredentials) Param() (ret interface{}) {
+	return c
+}
+
+func (c *GetCredentials) Validate() (ok bool) {
+	return c.ID != ""
+}
+
+// RemoveCredential defines "WebAuthn:RemoveCredential" command
+//
+// CredentialID is base64url-encoded, as Credential.MarshalJSON does.
+//
+// See GeckoDriver.prototype.removeCredential
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type RemoveCredential struct {
+	ID           string `json:"authenticatorId"`
+	CredentialID string `json:"credentialId"`
+}
+
+func (c *RemoveCredential) Command() (ret string) {
+	return "WebAuthn:RemoveCredential"
+}
+
+func (c *RemoveCredential) Param() (ret interface{}) {
+	return c
+}
+
+func (c *RemoveCredential) Validate() (ok bool) {
+	return c.ID != "" && c.CredentialID != ""
+}
+
+// RemoveAllCredentials defines "WebAuthn:RemoveAllCredentials" command
+//
+// See GeckoDriver.prototype.removeAllCredentials
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type RemoveAllCredentials struct {
+	ID string `json:"authenticatorId"`
+}
+
+func (c *RemoveAllCredentials) Command() (ret string) {
+	return "WebAuthn:RemoveAllCredentials"
+}
+
+func (c *RemoveAllCredentials) Param() (ret interface{}) {
+	return c
+}
+
+func (c *RemoveAllCredentials) Validate() (ok bool) {
+	return c.ID != ""
+}
+
+// SetUserVerified defines "WebAuthn:SetUserVerified" command
+//
+// See GeckoDriver.prototype.setUserVerified
+// https://github.com/mozilla/gecko-dev/blob/master/remote/marionette/driver.js
+type SetUserVerified struct {
+	ID       string `json:"authenticatorId"`
+	Verified bool   `json:"isUserVerified"`
+}
+
+func (c *SetUserVerified) Command() (ret string) {
+	return "WebAuthn:SetUserVerified"
+}
+
+func (c *SetUserVerified) Param() (ret interface{}) {
+	return c
+}
+
+func (c *SetUserVerified) Validate() (ok bool) {
+	return c.ID != ""
+}
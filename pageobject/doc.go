@@ -0,0 +1,49 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+// Package pageobject helps you to write page objects with declarative locators
+//
+// A page is a struct embedding Page, with element fields tagged by locators:
+//
+//	type Header struct {
+//	    pageobject.Component
+//	    User   *pageobject.Element `po:"css=.user"`
+//	    Logout *pageobject.Element `po:"text=Logout"`
+//	}
+//
+//	type LoginPage struct {
+//	    pageobject.Page `url:"https://example.com/login" ready:"css=form#login"`
+//	    Header   Header               `po:"css=header"`
+//	    Name     *pageobject.Element  `po:"css=#login input,nth=0"`
+//	    Password *pageobject.Element  `po:"css=input[type=password]"`
+//	    Errors   *pageobject.Elements `po:"css=.error,visible"`
+//	}
+//
+//	var p LoginPage
+//	if err := pageobject.Open(ctx, cl, &p); err != nil {
+//	    // page is not ready
+//	}
+//	p.Name.SendKeys("me")
+//
+// Fields are resolved lazily: each method call looks up the element again, so
+// page objects stay valid across re-rendering. Fields of nested components are
+// looked up inside the component's root element.
+//
+// To use with tabmgr, pass tab.Commander. Commands are still sent through the
+// tab, so the tab is activated as needed.
+//
+// Locators are set by "po" tags, which are not compatible with "mn" tags of
+// mnclient.Unmarshal. Locator tags are comma-separated. The first item is "strategy=value" where
+// strategy is one of css, xpath, id, name, class, tag, link, partial-link, text
+// and exact-text. Following items are filters applied in order: "nth=N",
+// "first", "last", "visible" and "has-text=text". See mnclient.Locator for
+// detail.
+package pageobject
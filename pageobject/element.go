@@ -0,0 +1,154 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package pageobject
+
+import (
+	"context"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mnclient"
+)
+
+// Element is a lazily resolved element field
+//
+// Every method looks up the element again, so it never goes stale.
+type Element struct {
+	cl  *mnclient.Commander
+	loc *mnclient.Locator
+}
+
+// NewElement creates an Element which is resolved by loc
+func NewElement(cl *mnclient.Commander, loc *mnclient.Locator) (ret *Element) {
+	return &Element{cl: cl, loc: loc}
+}
+
+// Locator returns the locator of this element
+func (e *Element) Locator() (ret *mnclient.Locator) {
+	return e.loc
+}
+
+// Get finds the element
+func (e *Element) Get() (ret *mnclient.Element, err error) {
+	return e.cl.Locate(e.loc)
+}
+
+// Exists checks if the element is in the document
+func (e *Element) Exists() (ok bool, err error) {
+	_, err = e.Get()
+	if x, yes := err.(*marionette.ErrDriver); yes && x.Type == marionette.ErrNoSuchElement {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Wait waits until the element presents and returns it
+func (e *Element) Wait(ctx context.Context, opts *mnclient.WaitOptions) (ret *mnclient.Element, err error) {
+	if err = e.cl.Wait(ctx, mnclient.Located(e.loc), opts); err != nil {
+		return
+	}
+	return e.Get()
+}
+
+// Click finds and clicks the element
+func (e *Element) Click() (err error) {
+	el, err := e.Get()
+	if err == nil {
+		err = el.Click()
+	}
+	return
+}
+
+// Clear finds the element and clears its text
+func (e *Element) Clear() (err error) {
+	el, err := e.Get()
+	if err == nil {
+		err = el.Clear()
+	}
+	return
+}
+
+// SendKeys finds the element and sends keystrokes to it
+func (e *Element) SendKeys(text string) (err error) {
+	el, err := e.Get()
+	if err == nil {
+		err = el.SendKeys(text)
+	}
+	return
+}
+
+// Text finds the element and retrieves its text
+func (e *Element) Text() (ret string, err error) {
+	el, err := e.Get()
+	if err == nil {
+		ret, err = el.Text()
+	}
+	return
+}
+
+// Attr finds the element and retrieves an attribute
+func (e *Element) Attr(key string) (ret string, err error) {
+	el, err := e.Get()
+	if err == nil {
+		ret, err = el.Attr(key)
+	}
+	return
+}
+
+// IsDisplayed finds the element and checks if it is displayed
+func (e *Element) IsDisplayed() (ret bool, err error) {
+	el, err := e.Get()
+	if err == nil {
+		ret, err = el.IsDisplayed()
+	}
+	return
+}
+
+// Unmarshal finds the element and fills dst, see mnclient.Unmarshal
+func (e *Element) Unmarshal(dst interface{}) (err error) {
+	el, err := e.Get()
+	if err == nil {
+		err = el.Unmarshal(dst)
+	}
+	return
+}
+
+// Elements is a lazily resolved list of elements
+type Elements struct {
+	cl  *mnclient.Commander
+	loc *mnclient.Locator
+}
+
+// NewElements creates an Elements which is resolved by loc
+func NewElements(cl *mnclient.Commander, loc *mnclient.Locator) (ret *Elements) {
+	return &Elements{cl: cl, loc: loc}
+}
+
+// Locator returns the locator of these elements
+func (e *Elements) Locator() (ret *mnclient.Locator) {
+	return e.loc
+}
+
+// All finds all matching elements
+func (e *Elements) All() (ret []*mnclient.Element, err error) {
+	return e.cl.LocateAll(e.loc)
+}
+
+// Count returns number of matching elements
+func (e *Elements) Count() (ret int, err error) {
+	els, err := e.All()
+	return len(els), err
+}
+
+// Nth returns n-th (0-based) matching element, negative n counts from the end
+func (e *Elements) Nth(n int) (ret *Element) {
+	return NewElement(e.cl, e.loc.Nth(n))
+}
@@ -0,0 +1,95 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package pageobject
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mnclient"
+)
+
+var strategies = map[string]func(string) *mnclient.Locator{
+	"css":          mnclient.CSS,
+	"xpath":        mnclient.XPath,
+	"text":         mnclient.Text,
+	"exact-text":   mnclient.ExactText,
+	"id":           byFunc(marionette.ID),
+	"name":         byFunc(marionette.Name),
+	"class":        byFunc(marionette.ClassName),
+	"tag":          byFunc(marionette.TagName),
+	"link":         byFunc(marionette.LinkText),
+	"partial-link": byFunc(marionette.PartialLinkText),
+}
+
+func byFunc(by marionette.FindStrategy) func(string) *mnclient.Locator {
+	return func(qstr string) *mnclient.Locator {
+		return mnclient.By(by, qstr)
+	}
+}
+
+// ParseLocator parses locator tag, see package document for syntax
+func ParseLocator(tag string) (ret *mnclient.Locator, err error) {
+	parts := strings.Split(tag, ",")
+	idx := strings.Index(parts[0], "=")
+	if idx < 0 {
+		return nil, errors.New("pageobject: invalid locator " + strconv.Quote(tag))
+	}
+	f, ok := strategies[parts[0][:idx]]
+	if !ok {
+		return nil, errors.New("pageobject: unknown strategy in " + strconv.Quote(tag))
+	}
+
+	qstr := []string{parts[0][idx+1:]}
+	var filters []func(*mnclient.Locator) *mnclient.Locator
+	for _, part := range parts[1:] {
+		key, arg := part, ""
+		if x := strings.Index(part, "="); x >= 0 {
+			key, arg = part[:x], part[x+1:]
+		}
+
+		switch key {
+		case "nth":
+			n, e := strconv.Atoi(arg)
+			if e != nil {
+				return nil, errors.New("pageobject: invalid nth in " + strconv.Quote(tag))
+			}
+			filters = append(filters, func(l *mnclient.Locator) *mnclient.Locator {
+				return l.Nth(n)
+			})
+		case "first":
+			filters = append(filters, (*mnclient.Locator).First)
+		case "last":
+			filters = append(filters, (*mnclient.Locator).Last)
+		case "visible":
+			filters = append(filters, (*mnclient.Locator).Visible)
+		case "has-text":
+			filters = append(filters, func(l *mnclient.Locator) *mnclient.Locator {
+				return l.HasText(arg)
+			})
+		default:
+			if len(filters) > 0 {
+				return nil, errors.New("pageobject: unknown filter in " + strconv.Quote(tag))
+			}
+			// comma inside value, like "css=h1, h2"
+			qstr = append(qstr, part)
+		}
+	}
+
+	ret = f(strings.Join(qstr, ","))
+	for _, f := range filters {
+		ret = f(ret)
+	}
+	return
+}
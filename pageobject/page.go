@@ -0,0 +1,225 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package pageobject
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/raohwork/marionette-go/mnclient"
+)
+
+// Page should be embedded in page structs
+//
+// URL to open and the ready condition can be set by tags of the embedded field:
+//
+//	type MyPage struct {
+//	    pageobject.Page `url:"https://example.com/" ready:"css=#app"`
+//	}
+//
+// You can also implement URLer and Readier for dynamic values.
+type Page struct {
+	// Commander which the page is bound to
+	Commander *mnclient.Commander
+	// filled from tag, can be overwritten before calling Open
+	URL   string
+	Ready *mnclient.Locator
+	// used when opening/verifying the page, default to 30 seconds timeout
+	WaitOptions *mnclient.WaitOptions
+}
+
+// Component can be embedded in nested component structs to access its root
+type Component struct {
+	Root *Element
+}
+
+// URLer is implemented by pages which compute URL at runtime
+type URLer interface {
+	PageURL() string
+}
+
+// Readier is implemented by pages which have extra ready conditions
+//
+// The condition is checked after Page.Ready.
+type Readier interface {
+	ReadyCondition() mnclient.Condition
+}
+
+const defaultTimeout = 30 * time.Second
+
+var (
+	typePage      = reflect.TypeOf(Page{})
+	typeComponent = reflect.TypeOf(Component{})
+	typeElement   = reflect.TypeOf(&Element{})
+	typeElements  = reflect.TypeOf(&Elements{})
+)
+
+// ErrNotStructPtr denotes the page is not a pointer to struct
+var ErrNotStructPtr = errors.New("pageobject: page must be pointer to struct")
+
+// structOf returns the struct which page points to
+func structOf(page interface{}) (ret reflect.Value, err error) {
+	v := reflect.ValueOf(page)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ret, ErrNotStructPtr
+	}
+	return v.Elem(), nil
+}
+
+// Bind fills element fields of page, page must be pointer to struct
+//
+// It does not send any command.
+func Bind(cl *mnclient.Commander, page interface{}) (err error) {
+	v, err := structOf(page)
+	if err != nil {
+		return
+	}
+
+	return bind(cl, v, nil)
+}
+
+func bind(cl *mnclient.Commander, v reflect.Value, scope *mnclient.Locator) (err error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		switch sf.Type {
+		case typePage:
+			if err = bindPage(cl, fv.Addr().Interface().(*Page), sf.Tag); err != nil {
+				return
+			}
+			continue
+		case typeComponent:
+			if scope != nil {
+				fv.Set(reflect.ValueOf(Component{Root: NewElement(cl, scope)}))
+			}
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup("po")
+		if !ok || tag == "-" {
+			continue
+		}
+		loc, err := ParseLocator(tag)
+		if err != nil {
+			return errors.New("pageobject: field " + sf.Name + ": " + err.Error())
+		}
+		if scope != nil {
+			loc = loc.Within(scope)
+		}
+
+		switch {
+		case sf.Type == typeElement:
+			fv.Set(reflect.ValueOf(NewElement(cl, loc)))
+		case sf.Type == typeElements:
+			fv.Set(reflect.ValueOf(NewElements(cl, loc)))
+		case sf.Type.Kind() == reflect.Struct:
+			err = bind(cl, fv, loc)
+		case sf.Type.Kind() == reflect.Ptr && sf.Type.Elem().Kind() == reflect.Struct:
+			if fv.IsNil() {
+				fv.Set(reflect.New(sf.Type.Elem()))
+			}
+			err = bind(cl, fv.Elem(), loc)
+		default:
+			err = errors.New("pageobject: unsupported type of field " + sf.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+func bindPage(cl *mnclient.Commander, p *Page, tag reflect.StructTag) (err error) {
+	p.Commander = cl
+	if url, ok := tag.Lookup("url"); ok && p.URL == "" {
+		p.URL = url
+	}
+	if ready, ok := tag.Lookup("ready"); ok && p.Ready == nil {
+		if p.Ready, err = ParseLocator(ready); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func pageOf(page interface{}) (ret *Page, err error) {
+	v, err := structOf(page)
+	if err != nil {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Type == typePage {
+			return v.Field(i).Addr().Interface().(*Page), nil
+		}
+	}
+	return
+}
+
+// Open binds the page, navigates to its URL and waits until it is ready
+//
+// URL comes from URLer if implemented, or from the embedded Page.
+func Open(ctx context.Context, cl *mnclient.Commander, page interface{}) (err error) {
+	if err = Bind(cl, page); err != nil {
+		return
+	}
+
+	var url string
+	if p, _ := pageOf(page); p != nil {
+		url = p.URL
+	}
+	if x, ok := page.(URLer); ok {
+		url = x.PageURL()
+	}
+	if url == "" {
+		return errors.New("pageobject: page has no url")
+	}
+
+	if err = cl.Navigate(url); err != nil {
+		return
+	}
+	return Verify(ctx, page)
+}
+
+// Verify waits until the page is ready, page must be bound
+//
+// It checks Page.Ready and Readier if implemented.
+func Verify(ctx context.Context, page interface{}) (err error) {
+	p, err := pageOf(page)
+	if err != nil {
+		return
+	}
+	if p == nil || p.Commander == nil {
+		return errors.New("pageobject: page is not bound")
+	}
+
+	opts := p.WaitOptions
+	if opts == nil {
+		opts = &mnclient.WaitOptions{Timeout: defaultTimeout}
+	}
+
+	if p.Ready != nil {
+		if err = p.Commander.Wait(ctx, mnclient.Located(p.Ready), opts); err != nil {
+			return
+		}
+	}
+	if x, ok := page.(Readier); ok {
+		err = p.Commander.Wait(ctx, x.ReadyCondition(), opts)
+	}
+	return
+}
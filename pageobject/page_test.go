@@ -0,0 +1,153 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package pageobject
+
+import (
+	"context"
+	"testing"
+
+	marionette "github.com/raohwork/marionette-go"
+	"github.com/raohwork/marionette-go/mnclient"
+	"github.com/raohwork/marionette-go/mncmd"
+)
+
+// fakeSender records commands and responds with an element to everything
+type fakeSender struct {
+	sent []mncmd.Command
+}
+
+func (s *fakeSender) Start() (err error) { return }
+func (s *fakeSender) Close()             {}
+func (s *fakeSender) Wait()              {}
+
+func (s *fakeSender) Sync(cmd mncmd.Command) (msg *marionette.Message, err error) {
+	s.sent = append(s.sent, cmd)
+	el := map[string]string{marionette.ElementType: "uuid"}
+	var data interface{} = map[string]interface{}{"value": el}
+	if _, ok := cmd.(*mncmd.ExecuteScript); ok {
+		data = map[string]interface{}{"value": []interface{}{el}}
+	}
+	return &marionette.Message{Type: 1, Data: data}, nil
+}
+
+func (s *fakeSender) Async(cmd mncmd.Command) (ch chan *marionette.Message, err error) {
+	msg, _ := s.Sync(cmd)
+	ch = make(chan *marionette.Message, 1)
+	ch <- msg
+	close(ch)
+	return
+}
+
+type testHeader struct {
+	Component
+	User *Element `po:"css=.user"`
+}
+
+type testPage struct {
+	Page   `url:"http://127.0.0.1/" ready:"css=#app,visible"`
+	Header testHeader  `po:"css=header"`
+	Menu   *testHeader `po:"xpath=//nav"`
+	Items  *Elements   `po:"css=li, dt,has-text=x"`
+	Skip   *Element
+}
+
+func TestParseLocator(t *testing.T) {
+	cases := map[string]string{
+		"css=a":              `css selector("a")`,
+		"css=h1, h2,first":   `css selector("h1, h2").nth(0)`,
+		"id=x,visible,nth=2": `id("x").visible().nth(2)`,
+		"text=a=b,last":      `text("a=b").nth(-1)`,
+		"partial-link=more":  `partial link text("more")`,
+	}
+	for tag, expect := range cases {
+		l, err := ParseLocator(tag)
+		if err != nil {
+			t.Errorf("cannot parse %s: %s", tag, err)
+			continue
+		}
+		if l.String() != expect {
+			t.Errorf("unexpected result of %s: %s", tag, l)
+		}
+	}
+
+	for _, tag := range []string{"a", "foo=a", "css=a,nth=x", "css=a,first,b"} {
+		if _, err := ParseLocator(tag); err == nil {
+			t.Errorf("expected error for %s", tag)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	cl := &mnclient.Commander{Sender: &fakeSender{}}
+	var p testPage
+	if err := Bind(cl, &p); err != nil {
+		t.Fatalf("cannot bind: %s", err)
+	}
+
+	if p.Commander != cl || p.URL != "http://127.0.0.1/" {
+		t.Errorf("unexpected page: %+v", p.Page)
+	}
+	if s := p.Ready.String(); s != `css selector("#app").visible()` {
+		t.Errorf("unexpected ready condition: %s", s)
+	}
+	if s := p.Header.Root.Locator().String(); s != `css selector("header")` {
+		t.Errorf("unexpected header root: %s", s)
+	}
+	if s := p.Header.User.Locator().String(); s != `css selector("header") >> css selector(".user")` {
+		t.Errorf("unexpected user: %s", s)
+	}
+	if p.Menu == nil || p.Menu.User.Locator().String() != `xpath("//nav") >> css selector(".user")` {
+		t.Errorf("unexpected menu: %+v", p.Menu)
+	}
+	if s := p.Items.Locator().String(); s != `css selector("li, dt").hasText("x")` {
+		t.Errorf("unexpected items: %s", s)
+	}
+	if p.Skip != nil {
+		t.Errorf("untagged field should be skipped")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	sender := &fakeSender{}
+	cl := &mnclient.Commander{Sender: sender}
+	var p testPage
+	if err := Open(context.Background(), cl, &p); err != nil {
+		t.Fatalf("cannot open: %s", err)
+	}
+
+	if len(sender.sent) != 2 {
+		t.Fatalf("unexpected commands: %+v", sender.sent)
+	}
+	if nav, ok := sender.sent[0].(*mncmd.Navigate); !ok || nav.URL != p.URL {
+		t.Errorf("unexpected navigation: %+v", sender.sent[0])
+	}
+	if _, ok := sender.sent[1].(*mncmd.ExecuteScript); !ok {
+		t.Errorf("unexpected ready check: %+v", sender.sent[1])
+	}
+
+	if ok, err := p.Header.User.Exists(); !ok || err != nil {
+		t.Errorf("unexpected result of Exists: %v, %v", ok, err)
+	}
+}
+
+func TestNotStructPtr(t *testing.T) {
+	cl := &mnclient.Commander{Sender: &fakeSender{}}
+	var p testPage
+	for _, page := range []interface{}{p, (*testPage)(nil), new(int), nil} {
+		if err := Bind(cl, page); err != ErrNotStructPtr {
+			t.Errorf("unexpected error of Bind(%T): %v", page, err)
+		}
+		if err := Verify(context.Background(), page); err != ErrNotStructPtr {
+			t.Errorf("unexpected error of Verify(%T): %v", page, err)
+		}
+	}
+}
@@ -29,7 +29,9 @@ import (
 //
 //   - CloseChromeWindow
 //   - CloseWindow
+//   - DeleteSession
 //   - NewSession
+//   - NewSessionWithCaps
 //   - NewWindow
 //   - SwitchToWindow
 //   - SwitchToWindowBG
@@ -66,9 +68,15 @@ func (t *Tab) CloseChromeWindow() (handles []string, err error) {
 func (t *Tab) CloseWindow() (handles []string, err error) {
 	panic(errors.New("CloseWindow is not supported in Columbine"))
 }
+func (t *Tab) DeleteSession() (err error) {
+	panic(errors.New("DeleteSession is not supported in Columbine"))
+}
 func (t *Tab) NewSession() (a string, b *marionette.Capabilities, err error) {
 	panic(errors.New("NewSession is not supported in Columbine"))
 }
+func (t *Tab) NewSessionWithCaps(req *marionette.CapabilitiesRequest) (a string, b *marionette.Capabilities, err error) {
+	panic(errors.New("NewSessionWithCaps is not supported in Columbine"))
+}
 func (t *Tab) NewWindow(typ string, focus bool) (a, b string, err error) {
 	panic(errors.New("NewWindow is not supported in Columbine"))
 }
@@ -90,6 +98,8 @@ func (t *Tab) GetName() (ret string) {
 // waits a second between each attempt.
 //
 // Should be useful if you're manipulating dynamic generated pages like SPA.
+//
+// See mnclient.Commander.Wait for more flexible conditions and polling interval.
 func (t *Tab) WaitFor(qstr string, tries int) (ret *marionette.WebElement, err error) {
 	if tries < 1 {
 		tries = 1
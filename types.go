@@ -11,7 +11,11 @@
 
 package marionette
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+)
 
 const (
 	ChromeContext  = "chrome"
@@ -52,7 +56,16 @@ type Timeouts struct {
 	Script   int `json:"script,omitempty"`
 }
 
+// Bool returns a pointer to b, which is handy to fill optional boolean fields like
+// Capabilities.SetWindowRect
+func Bool(b bool) *bool {
+	return &b
+}
+
 // Capabilities represents marionette server capabilities
+//
+// Boolean capabilities which are meaningful when false (like SetWindowRect) are
+// pointers, nil means not specified.
 type Capabilities struct {
 	// web driver
 	BrowserName               string    `json:"browserName,omitempty"`
@@ -62,9 +75,9 @@ type Capabilities struct {
 	AcceptInsecureCerts       bool      `json:"acceptInsecureCerts,omitempty"`
 	PageLoadStrategy          string    `json:"pageLoadStrategy,omitempty"`
 	Proxy                     *Proxy    `json:"proxy,omitempty"`
-	SetWindowRect             bool      `json:"setWindowRect,omitempty"`
+	SetWindowRect             *bool     `json:"setWindowRect,omitempty"`
 	Timeouts                  *Timeouts `json:"timeouts,omitempty"`
-	StrictFileInteractability bool      `json:"strictFileInteractability,omitempty"`
+	StrictFileInteractability *bool     `json:"strictFileInteractability,omitempty"`
 	UnhandledPromptBehavior   string    `json:"unhandledPromptBehavior,omitempty"`
 
 	// features
@@ -79,6 +92,15 @@ type Capabilities struct {
 	ShutdownTimeout      int    `json:"shutdownTimeout,omitempty"`
 	SpecialPointerOrigin bool   `json:"moz:useNonSpecCompliantPointerOrigin,omitempty"`
 	WebdriverClick       bool   `json:"moz:webdriverClick,omitempty"`
+
+	FirefoxOptions *FirefoxOptions `json:"moz:firefoxOptions,omitempty"`
+	// address of remote debugger in response, see EnableDebugger
+	DebuggerAddress string `json:"moz:debuggerAddress,omitempty"`
+	// requests moz:debuggerAddress when creating new session
+	EnableDebugger bool `json:"-"`
+
+	// unknown keys (like other vendor specific capabilities) are kept here
+	Extra map[string]interface{} `json:"-"`
 }
 
 // Rect represents size/placement info about a window/element/...
@@ -111,16 +133,132 @@ const (
 	WindowType        = "window-fcc6-11e5-b4f8-330a88ab9d7f"
 	FrameType         = "frame-075b-4da1-b6ba-e579c2d3230a"
 	ChromeElementType = "chromeelement-9fc5-4b51-a3c8-01716eedeb04"
+	ShadowRootType    = "shadow-6066-11e4-a52e-4f735466cecf"
 )
 
+// legacy key sent along with ElementType by older Firefox
+const legacyElementKey = "ELEMENT"
+
 // WebElement is an element (window/frame/html element) referenced by an UUID
+//
+// It is encoded as web element reference like {ElementType: UUID}, so you can
+// pass it to scripts as argument, even nested in slices, maps or structs.
 type WebElement struct {
 	Type string
 	UUID string
 }
 
-func (el *WebElement) MarshalJSON() (data []byte, err error) {
-	return json.Marshal(el.UUID)
+// MarshalJSON encodes el as web element reference, Type defaults to ElementType
+func (el WebElement) MarshalJSON() (data []byte, err error) {
+	typ := el.Type
+	if typ == "" {
+		typ = ElementType
+	}
+	return json.Marshal(map[string]string{typ: el.UUID})
+}
+
+// UnmarshalJSON decodes web element reference, or bare UUID as ElementType
+func (el *WebElement) UnmarshalJSON(data []byte) (err error) {
+	var uuid string
+	if err = json.Unmarshal(data, &uuid); err == nil {
+		*el = WebElement{Type: ElementType, UUID: uuid}
+		return
+	}
+
+	var m map[string]interface{}
+	if err = json.Unmarshal(data, &m); err != nil {
+		return
+	}
+	ret := refFromMap(m)
+	if ret == nil {
+		return errors.New("marionette: not a web element reference: " + string(data))
+	}
+	*el = *ret
+	return
+}
+
+func isRefType(typ string) (ok bool) {
+	switch typ {
+	case ElementType, WindowType, FrameType, ChromeElementType, ShadowRootType:
+		return true
+	}
+	return
+}
+
+// refFromMap converts decoded web element reference, returns nil if m is not
+func refFromMap(m map[string]interface{}) (ret *WebElement) {
+	if len(m) == 0 || len(m) > 2 {
+		return
+	}
+	for k, v := range m {
+		uuid, ok := v.(string)
+		switch {
+		case !ok:
+			return nil
+		case isRefType(k):
+			ret = &WebElement{Type: k, UUID: uuid}
+		case k != legacyElementKey:
+			return nil
+		}
+	}
+	return
+}
+
+// ResolveElements replaces web element references in v with *WebElement
+//
+// v must be a pointer. It walks through slices, maps and structs, and replaces
+// references held in interface{}, like results of scripts decoded into
+// []interface{} or map[string]interface{}. Typed *WebElement fields are decoded
+// by UnmarshalJSON, not here.
+func ResolveElements(v interface{}) {
+	if v == nil {
+		return
+	}
+	resolveValue(reflect.ValueOf(v))
+}
+
+func resolveValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			resolveValue(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		inner := v.Elem()
+		if m, ok := inner.Interface().(map[string]interface{}); ok {
+			if el := refFromMap(m); el != nil {
+				if v.CanSet() {
+					v.Set(reflect.ValueOf(el))
+				}
+				return
+			}
+		}
+		resolveValue(inner)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				resolveValue(f)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			resolveValue(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		for _, k := range v.MapKeys() {
+			// map values are not settable, resolve a copy and put it back
+			tmp := reflect.New(v.Type().Elem()).Elem()
+			tmp.Set(v.MapIndex(k))
+			resolveValue(tmp)
+			v.SetMapIndex(k, tmp)
+		}
+	}
 }
 
 type Cookie struct {
@@ -143,6 +281,14 @@ const (
 	LANDSCAPE_SECONDARY = "landscape-secondary"
 )
 
+const (
+	// permission states, see
+	// https://w3c.github.io/permissions/#dom-permissionstate
+	PermissionGranted = "granted"
+	PermissionDenied  = "denied"
+	PermissionPrompt  = "prompt"
+)
+
 const (
 	// window types
 	FirefoxWindow     = "navigator:browser"
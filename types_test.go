@@ -0,0 +1,130 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestWebElementMarshal(t *testing.T) {
+	el := &WebElement{Type: ElementType, UUID: "a"}
+	frame := WebElement{Type: FrameType, UUID: "b"}
+	var none *WebElement
+
+	data, err := json.Marshal([]interface{}{
+		el,
+		frame,
+		&WebElement{UUID: "c"},
+		none,
+		map[string]interface{}{"x": []*WebElement{el}},
+		struct {
+			El WebElement `json:"el"`
+		}{frame},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expect := `[` +
+		`{"element-6066-11e4-a52e-4f735466cecf":"a"},` +
+		`{"frame-075b-4da1-b6ba-e579c2d3230a":"b"},` +
+		`{"element-6066-11e4-a52e-4f735466cecf":"c"},` +
+		`null,` +
+		`{"x":[{"element-6066-11e4-a52e-4f735466cecf":"a"}]},` +
+		`{"el":{"frame-075b-4da1-b6ba-e579c2d3230a":"b"}}` +
+		`]`
+	if string(data) != expect {
+		t.Fatalf("unexpected json: %s", data)
+	}
+}
+
+func TestWebElementUnmarshal(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   string
+		expect WebElement
+	}{
+		{"BareUUID", `"a"`, WebElement{Type: ElementType, UUID: "a"}},
+		{"Element", `{"element-6066-11e4-a52e-4f735466cecf":"a"}`, WebElement{Type: ElementType, UUID: "a"}},
+		{"Legacy", `{"ELEMENT":"a","element-6066-11e4-a52e-4f735466cecf":"a"}`, WebElement{Type: ElementType, UUID: "a"}},
+		{"Window", `{"window-fcc6-11e5-b4f8-330a88ab9d7f":"w"}`, WebElement{Type: WindowType, UUID: "w"}},
+		{"ShadowRoot", `{"shadow-6066-11e4-a52e-4f735466cecf":"s"}`, WebElement{Type: ShadowRootType, UUID: "s"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var el WebElement
+			if err := json.Unmarshal([]byte(c.data), &el); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if el != c.expect {
+				t.Fatalf("unexpected element: %+v", el)
+			}
+		})
+	}
+
+	var el WebElement
+	if err := json.Unmarshal([]byte(`{"a":"b"}`), &el); err == nil {
+		t.Fatal("expected error for non-reference object")
+	}
+}
+
+func TestResolveElements(t *testing.T) {
+	ref := func(uuid string) map[string]interface{} {
+		return map[string]interface{}{ElementType: uuid}
+	}
+	el := func(uuid string) *WebElement {
+		return &WebElement{Type: ElementType, UUID: uuid}
+	}
+
+	var dest struct {
+		Any   interface{}
+		List  []interface{}
+		Map   map[string]interface{}
+		Typed map[string]string
+		other interface{}
+	}
+	dest.Any = ref("a")
+	dest.List = []interface{}{ref("b"), "x", []interface{}{ref("c")}}
+	dest.Map = map[string]interface{}{
+		"d":      ref("d"),
+		"nested": map[string]interface{}{"e": ref("e")},
+		"plain":  map[string]interface{}{"k": "v"},
+	}
+	dest.Typed = map[string]string{ElementType: "f"}
+	dest.other = ref("g")
+	ResolveElements(&dest)
+
+	if !reflect.DeepEqual(dest.Any, el("a")) {
+		t.Errorf("unexpected Any: %#v", dest.Any)
+	}
+	expectList := []interface{}{el("b"), "x", []interface{}{el("c")}}
+	if !reflect.DeepEqual(dest.List, expectList) {
+		t.Errorf("unexpected List: %#v", dest.List)
+	}
+	expectMap := map[string]interface{}{
+		"d":      el("d"),
+		"nested": map[string]interface{}{"e": el("e")},
+		"plain":  map[string]interface{}{"k": "v"},
+	}
+	if !reflect.DeepEqual(dest.Map, expectMap) {
+		t.Errorf("unexpected Map: %#v", dest.Map)
+	}
+	if !reflect.DeepEqual(dest.Typed, map[string]string{ElementType: "f"}) {
+		t.Errorf("typed map should not change: %#v", dest.Typed)
+	}
+	if !reflect.DeepEqual(dest.other, ref("g")) {
+		t.Errorf("unexported field should not change: %#v", dest.other)
+	}
+}
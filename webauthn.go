@@ -0,0 +1,123 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+const (
+	// protocols of virtual authenticator
+	AuthenticatorCTAP1   = "ctap1/u2f"
+	AuthenticatorCTAP2   = "ctap2"
+	AuthenticatorCTAP2_1 = "ctap2_1"
+)
+
+const (
+	// transports of virtual authenticator
+	AuthenticatorUSB      = "usb"
+	AuthenticatorNFC      = "nfc"
+	AuthenticatorBLE      = "ble"
+	AuthenticatorHybrid   = "hybrid"
+	AuthenticatorInternal = "internal"
+)
+
+// VirtualAuthenticator represents options to create a virtual authenticator
+//
+// See https://w3c.github.io/webauthn/#sctn-automation-virtual-authenticators
+type VirtualAuthenticator struct {
+	Protocol            string `json:"protocol"`
+	Transport           string `json:"transport"`
+	HasResidentKey      bool   `json:"hasResidentKey"`
+	HasUserVerification bool   `json:"hasUserVerification"`
+	IsUserConsenting    bool   `json:"isUserConsenting"`
+	IsUserVerified      bool   `json:"isUserVerified"`
+}
+
+// Credential represents a public key credential stored in virtual authenticator
+//
+// Binary fields are encoded in base64url when sending to/receiving from
+// marionette server. PrivateKey is PKCS#8 encoded.
+type Credential struct {
+	ID                   []byte
+	IsResidentCredential bool
+	RpID                 string
+	PrivateKey           []byte
+	UserHandle           []byte
+	SignCount            int
+}
+
+type credentialJSON struct {
+	ID                   string `json:"credentialId"`
+	IsResidentCredential bool   `json:"isResidentCredential"`
+	RpID                 string `json:"rpId"`
+	PrivateKey           string `json:"privateKey"`
+	UserHandle           string `json:"userHandle,omitempty"`
+	SignCount            int    `json:"signCount"`
+}
+
+func (c *Credential) MarshalJSON() (data []byte, err error) {
+	enc := base64.RawURLEncoding
+	x := credentialJSON{
+		ID:                   enc.EncodeToString(c.ID),
+		IsResidentCredential: c.IsResidentCredential,
+		RpID:                 c.RpID,
+		PrivateKey:           enc.EncodeToString(c.PrivateKey),
+		SignCount:            c.SignCount,
+	}
+	if len(c.UserHandle) > 0 {
+		x.UserHandle = enc.EncodeToString(c.UserHandle)
+	}
+
+	return json.Marshal(x)
+}
+
+func (c *Credential) UnmarshalJSON(data []byte) (err error) {
+	var x credentialJSON
+	if err = json.Unmarshal(data, &x); err != nil {
+		return
+	}
+
+	dec := func(s string) (ret []byte, err error) {
+		if s == "" {
+			return
+		}
+		// padding is optional in base64url
+		return base64.RawURLEncoding.DecodeString(
+			trimPadding(s),
+		)
+	}
+
+	if c.ID, err = dec(x.ID); err != nil {
+		return
+	}
+	if c.PrivateKey, err = dec(x.PrivateKey); err != nil {
+		return
+	}
+	if c.UserHandle, err = dec(x.UserHandle); err != nil {
+		return
+	}
+	c.IsResidentCredential = x.IsResidentCredential
+	c.RpID = x.RpID
+	c.SignCount = x.SignCount
+
+	return
+}
+
+func trimPadding(s string) (ret string) {
+	ret = s
+	for len(ret) > 0 && ret[len(ret)-1] == '=' {
+		ret = ret[:len(ret)-1]
+	}
+	return
+}
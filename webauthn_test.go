@@ -0,0 +1,58 @@
+// This file is part of marionette-go
+//
+// marionette-go is distributed in two licenses: The Mozilla Public License,
+// v. 2.0 and the GNU Lesser Public License.
+//
+// marionette-go is distributed in the hope that it will be useful, but WITHOUT
+// ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS
+// FOR A PARTICULAR PURPOSE.
+//
+// See License.txt for further information.
+
+package marionette
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCredentialEncode(t *testing.T) {
+	c := &Credential{
+		ID:         []byte{0xfb, 0xff, 0x01},
+		RpID:       "localhost",
+		PrivateKey: []byte("key"),
+		SignCount:  1,
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("cannot marshal credential: %s", err)
+	}
+	expected := `{"credentialId":"-_8B","isResidentCredential":false,"rpId":"localhost","privateKey":"a2V5","signCount":1}`
+	if str := string(data); str != expected {
+		t.Fatalf("unexpected result: %s", str)
+	}
+}
+
+func TestCredentialDecode(t *testing.T) {
+	data := `{"credentialId":"-_8B","isResidentCredential":true,"rpId":"localhost","privateKey":"a2V5","userHandle":"dXNlcg==","signCount":3}`
+
+	var c Credential
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatalf("cannot unmarshal credential: %s", err)
+	}
+
+	if !bytes.Equal(c.ID, []byte{0xfb, 0xff, 0x01}) {
+		t.Errorf("unexpected id: %v", c.ID)
+	}
+	if string(c.PrivateKey) != "key" {
+		t.Errorf("unexpected private key: %s", c.PrivateKey)
+	}
+	if string(c.UserHandle) != "user" {
+		t.Errorf("unexpected user handle: %s", c.UserHandle)
+	}
+	if !c.IsResidentCredential || c.RpID != "localhost" || c.SignCount != 3 {
+		t.Errorf("unexpected credential: %+v", c)
+	}
+}